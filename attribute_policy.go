@@ -0,0 +1,139 @@
+package otelcmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	internal_semconv "github.com/cmd-stream/otelcmd-stream-go/internal/semconv"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// MaskedValue replaces values of the masked attributes.
+const MaskedValue = "***"
+
+// AttributePolicy restricts attributes returned by the user-supplied
+// functions: SpanAttributesFn, SpanResultEventAttributesFn,
+// CmdMetricAttributesFn and ResultMetricAttributesFn.
+//
+// An attribute with a key from DeniedKeys is dropped. If AllowedKeys is not
+// empty, an attribute with a key not from this list is dropped too. Values of
+// the HashedKeys attributes are replaced with a SHA-256 hash, values of the
+// MaskedKeys attributes - with MaskedValue. String values, and elements of
+// string slice values, longer than MaxValueLen bytes are truncated on a rune
+// boundary, a zero MaxValueLen means no limit.
+//
+// Dropped and truncated attributes are counted by the
+// "cmd-stream.attribute.policy.violations" metric, by the kind of violation.
+type AttributePolicy struct {
+	AllowedKeys []string
+	DeniedKeys  []string
+	HashedKeys  []string
+	MaskedKeys  []string
+	MaxValueLen int
+}
+
+func newAttributeFilter(policy AttributePolicy,
+	meter metric.Meter) *attributeFilter {
+	return &attributeFilter{
+		allowed:     keySet(policy.AllowedKeys),
+		denied:      keySet(policy.DeniedKeys),
+		hashed:      keySet(policy.HashedKeys),
+		masked:      keySet(policy.MaskedKeys),
+		maxValueLen: policy.MaxValueLen,
+		violations:  internal_semconv.NewAttributePolicyViolations(meter),
+	}
+}
+
+// attributeFilter is a prepared for use AttributePolicy.
+type attributeFilter struct {
+	allowed     map[attribute.Key]struct{}
+	denied      map[attribute.Key]struct{}
+	hashed      map[attribute.Key]struct{}
+	masked      map[attribute.Key]struct{}
+	maxValueLen int
+	violations  internal_semconv.AttributePolicyViolations
+}
+
+func (f *attributeFilter) apply(ctx context.Context,
+	attrs []attribute.KeyValue) []attribute.KeyValue {
+	if f == nil || len(attrs) == 0 {
+		return attrs
+	}
+	filtered := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		if _, ok := f.denied[attr.Key]; ok {
+			f.violations.Record(ctx, semconv.Denied)
+			continue
+		}
+		if f.allowed != nil {
+			if _, ok := f.allowed[attr.Key]; !ok {
+				f.violations.Record(ctx, semconv.NotAllowed)
+				continue
+			}
+		}
+		if _, ok := f.hashed[attr.Key]; ok {
+			sum := sha256.Sum256([]byte(attr.Value.Emit()))
+			attr = attr.Key.String(hex.EncodeToString(sum[:]))
+		} else if _, ok := f.masked[attr.Key]; ok {
+			attr = attr.Key.String(MaskedValue)
+		} else if truncated, ok := f.truncate(attr); ok {
+			attr = truncated
+			f.violations.Record(ctx, semconv.Truncated)
+		}
+		filtered = append(filtered, attr)
+	}
+	return filtered
+}
+
+// truncate truncates the string value, or each element of the string slice
+// value, of the attribute to maxValueLen bytes. It returns false if nothing
+// was truncated.
+func (f *attributeFilter) truncate(attr attribute.KeyValue) (
+	attribute.KeyValue, bool) {
+	if f.maxValueLen <= 0 {
+		return attr, false
+	}
+	switch attr.Value.Type() {
+	case attribute.STRING:
+		if len(attr.Value.AsString()) > f.maxValueLen {
+			return attr.Key.String(truncateString(attr.Value.AsString(),
+				f.maxValueLen)), true
+		}
+	case attribute.STRINGSLICE:
+		var (
+			values    = attr.Value.AsStringSlice()
+			truncated bool
+		)
+		for i, value := range values {
+			if len(value) > f.maxValueLen {
+				values[i] = truncateString(value, f.maxValueLen)
+				truncated = true
+			}
+		}
+		if truncated {
+			return attr.Key.StringSlice(values), true
+		}
+	}
+	return attr, false
+}
+
+// applyAttributePolicy applies AttributePolicy, if any, to the attributes
+// returned by a user-supplied function.
+func (o Options[T]) applyAttributePolicy(ctx context.Context,
+	attrs []attribute.KeyValue) []attribute.KeyValue {
+	return o.attributeFilter.apply(ctx, attrs)
+}
+
+func keySet(keys []string) (set map[attribute.Key]struct{}) {
+	if len(keys) == 0 {
+		return
+	}
+	set = make(map[attribute.Key]struct{}, len(keys))
+	for _, key := range keys {
+		set[attribute.Key(key)] = struct{}{}
+	}
+	return
+}
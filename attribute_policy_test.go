@@ -0,0 +1,124 @@
+package otelcmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"github.com/cmd-stream/otelcmd-stream-go/test/mock"
+	asserterror "github.com/ymz-ncnk/assert/error"
+	"github.com/ymz-ncnk/mok"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+func TestAttributePolicy(t *testing.T) {
+	t.Run("Policy should be applied to all attributes", func(t *testing.T) {
+		var (
+			sum        = sha256.Sum256([]byte("alice@example.com"))
+			violations []attribute.Set
+			counter    = mock.NewInt64Counter()
+			meter      = mock.NewMeter().RegisterInt64Counter(
+				func(name string, options ...metric.Int64CounterOption) (
+					metric.Int64Counter, error) {
+					asserterror.Equal(t, name,
+						semconv.CmdStreamAttributePolicyViolationCountName)
+					return counter, nil
+				},
+			)
+			recordViolation = func(ctx context.Context, incr int64,
+				options ...metric.AddOption) {
+				config := metric.NewAddConfig(options)
+				violations = append(violations, config.Attributes())
+			}
+			o = Options[any]{
+				AttributePolicy: &AttributePolicy{
+					AllowedKeys: []string{"user.email", "user.phone", "comment"},
+					DeniedKeys:  []string{"user.phone"},
+					HashedKeys:  []string{"user.email"},
+					MaskedKeys:  []string{"comment"},
+					MaxValueLen: 3,
+				},
+				Meter: meter,
+			}
+		)
+		counter.RegisterAdd(recordViolation).RegisterAdd(recordViolation)
+		Apply(nil, &o)
+
+		attrs := o.applyAttributePolicy(context.Background(), []attribute.KeyValue{
+			attribute.String("user.email", "alice@example.com"),
+			attribute.String("user.phone", "555-0100"),
+			attribute.String("comment", "some text"),
+			attribute.String("unknown", "value"),
+		})
+		asserterror.EqualDeep(t, attrs, []attribute.KeyValue{
+			attribute.String("user.email", hex.EncodeToString(sum[:])),
+			attribute.String("comment", MaskedValue),
+		})
+		asserterror.EqualDeep(t, violations, []attribute.Set{
+			attribute.NewSet(
+				semconv.CmdStreamAttributeViolationKey.String(string(semconv.Denied)),
+			),
+			attribute.NewSet(
+				semconv.CmdStreamAttributeViolationKey.String(string(semconv.NotAllowed)),
+			),
+		})
+		asserterror.EqualDeep(t, mok.CheckCalls([]*mok.Mock{meter.Mock,
+			counter.Mock}), mok.EmptyInfomap)
+	})
+
+	t.Run("Long values should be truncated", func(t *testing.T) {
+		var (
+			violations []attribute.Set
+			counter    = mock.NewInt64Counter()
+			meter      = mock.NewMeter().RegisterInt64Counter(
+				func(name string, options ...metric.Int64CounterOption) (
+					metric.Int64Counter, error) {
+					return counter, nil
+				},
+			)
+			recordViolation = func(ctx context.Context, incr int64,
+				options ...metric.AddOption) {
+				config := metric.NewAddConfig(options)
+				violations = append(violations, config.Attributes())
+			}
+			o = Options[any]{
+				AttributePolicy: &AttributePolicy{MaxValueLen: 3},
+				Meter:           meter,
+			}
+			truncated = attribute.NewSet(
+				semconv.CmdStreamAttributeViolationKey.String(string(semconv.Truncated)),
+			)
+		)
+		counter.RegisterAdd(recordViolation).RegisterAdd(recordViolation).
+			RegisterAdd(recordViolation)
+		Apply(nil, &o)
+		attrs := o.applyAttributePolicy(context.Background(), []attribute.KeyValue{
+			attribute.String("key", "value"),
+			attribute.String("utf8", "aбв"),
+			attribute.Int("int", 12345),
+			attribute.StringSlice("slice", []string{"ab", "abcd"}),
+			attribute.StringSlice("short", []string{"ab", "abc"}),
+		})
+		asserterror.EqualDeep(t, attrs, []attribute.KeyValue{
+			attribute.String("key", "val"),
+			attribute.String("utf8", "aб"),
+			attribute.Int("int", 12345),
+			attribute.StringSlice("slice", []string{"ab", "abc"}),
+			attribute.StringSlice("short", []string{"ab", "abc"}),
+		})
+		asserterror.EqualDeep(t, violations, []attribute.Set{truncated,
+			truncated, truncated})
+		asserterror.EqualDeep(t, mok.CheckCalls([]*mok.Mock{meter.Mock,
+			counter.Mock}), mok.EmptyInfomap)
+	})
+
+	t.Run("Without a policy attributes should be returned as is",
+		func(t *testing.T) {
+			attrs := []attribute.KeyValue{attribute.String("key", "value")}
+			asserterror.EqualDeep(t, Options[any]{}.applyAttributePolicy(
+				context.Background(), attrs), attrs)
+		})
+}
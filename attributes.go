@@ -0,0 +1,210 @@
+package otelcmd
+
+import (
+	"context"
+	"net"
+	"slices"
+
+	"github.com/cmd-stream/cmd-stream-go/sender/hooks"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ContextAttributesFn returns span attributes derived from the context.
+type ContextAttributesFn func(ctx context.Context) []attribute.KeyValue
+
+// StaticSpanAttributes returns a SpanAttributesFn that always returns the
+// specified attributes.
+func StaticSpanAttributes[T any](attrs ...attribute.KeyValue) SpanAttributesFn[T] {
+	return func(remoteAddr net.Addr, sentCmd hooks.SentCmd[T]) []attribute.KeyValue {
+		return attrs
+	}
+}
+
+// StaticMetricAttributes returns a CmdMetricAttributesFn that always returns
+// the specified attributes.
+func StaticMetricAttributes[T any](attrs ...attribute.KeyValue) CmdMetricAttributesFn[T] {
+	return func(sentCmd hooks.SentCmd[T], status semconv.CmdStreamCommandStatus,
+		elapsedTime float64) []attribute.KeyValue {
+		return attrs
+	}
+}
+
+type contextAttributesKey struct{}
+
+// ContextWithAttributes returns a copy of the context with the specified
+// attributes added. They can be retrieved with ContextAttributes.
+func ContextWithAttributes(ctx context.Context,
+	attrs ...attribute.KeyValue) context.Context {
+	return context.WithValue(ctx, contextAttributesKey{},
+		mergeAttributes(ContextAttributes(ctx), attrs))
+}
+
+// ContextAttributes is a ContextAttributesFn that returns attributes added to
+// the context with ContextWithAttributes.
+func ContextAttributes(ctx context.Context) []attribute.KeyValue {
+	attrs, _ := ctx.Value(contextAttributesKey{}).([]attribute.KeyValue)
+	return attrs
+}
+
+// mergeAttributes concatenates the attribute lists and removes duplicate keys,
+// the last value wins.
+func mergeAttributes(lists ...[]attribute.KeyValue) []attribute.KeyValue {
+	var (
+		l     = 0
+		index map[attribute.Key]int
+		attrs []attribute.KeyValue
+	)
+	for i := range lists {
+		l += len(lists[i])
+	}
+	if l == 0 {
+		return nil
+	}
+	index = make(map[attribute.Key]int, l)
+	attrs = make([]attribute.KeyValue, 0, l)
+	for i := range lists {
+		for _, attr := range lists[i] {
+			if j, ok := index[attr.Key]; ok {
+				attrs[j] = attr
+				continue
+			}
+			index[attr.Key] = len(attrs)
+			attrs = append(attrs, attr)
+		}
+	}
+	return attrs
+}
+
+func chainSpanAttributesFns[T any](prev,
+	fn SpanAttributesFn[T]) SpanAttributesFn[T] {
+	if prev == nil {
+		return fn
+	}
+	if fn == nil {
+		return prev
+	}
+	return func(remoteAddr net.Addr, sentCmd hooks.SentCmd[T]) []attribute.KeyValue {
+		return mergeAttributes(prev(remoteAddr, sentCmd), fn(remoteAddr, sentCmd))
+	}
+}
+
+func chainSpanResultEventAttributesFns[T any](prev,
+	fn SpanResultEventAttributesFn[T]) SpanResultEventAttributesFn[T] {
+	if prev == nil {
+		return fn
+	}
+	if fn == nil {
+		return prev
+	}
+	return func(sentCmd hooks.SentCmd[T],
+		recvResult hooks.ReceivedResult) []attribute.KeyValue {
+		return mergeAttributes(prev(sentCmd, recvResult), fn(sentCmd, recvResult))
+	}
+}
+
+func chainCmdMetricAttributesFns[T any](prev,
+	fn CmdMetricAttributesFn[T]) CmdMetricAttributesFn[T] {
+	if prev == nil {
+		return fn
+	}
+	if fn == nil {
+		return prev
+	}
+	return func(sentCmd hooks.SentCmd[T], status semconv.CmdStreamCommandStatus,
+		elapsedTime float64) []attribute.KeyValue {
+		return mergeAttributes(prev(sentCmd, status, elapsedTime),
+			fn(sentCmd, status, elapsedTime))
+	}
+}
+
+func chainResultMetricAttributesFns[T any](prev,
+	fn ResultMetricAttributesFn[T]) ResultMetricAttributesFn[T] {
+	if prev == nil {
+		return fn
+	}
+	if fn == nil {
+		return prev
+	}
+	return func(sentCmd hooks.SentCmd[T], recvResult hooks.ReceivedResult,
+		elapsedTime float64) []attribute.KeyValue {
+		return mergeAttributes(prev(sentCmd, recvResult, elapsedTime),
+			fn(sentCmd, recvResult, elapsedTime))
+	}
+}
+
+func chainContextAttributesFns(prev,
+	fn ContextAttributesFn) ContextAttributesFn {
+	if prev == nil {
+		return fn
+	}
+	if fn == nil {
+		return prev
+	}
+	return func(ctx context.Context) []attribute.KeyValue {
+		return mergeAttributes(prev(ctx), fn(ctx))
+	}
+}
+
+// userSpanAttributes returns attributes of SpanAttributesFn, the Command's
+// SpanAttributer and ContextAttributesFn with the AttributePolicy applied.
+//
+// The user* methods may return a slice owned by a user-supplied function, it
+// is clipped, so appending to it copies the attributes instead of writing to
+// the user's backing array.
+func (o Options[T]) userSpanAttributes(ctx context.Context, remoteAddr net.Addr,
+	sentCmd hooks.SentCmd[T]) (attrs []attribute.KeyValue) {
+	if o.SpanAttributesFn != nil {
+		attrs = o.SpanAttributesFn(remoteAddr, sentCmd)
+	}
+	if cmdAttrs := cmdSpanAttributes(sentCmd.Cmd); len(cmdAttrs) > 0 {
+		attrs = mergeAttributes(attrs, cmdAttrs)
+	}
+	if o.ContextAttributesFn != nil {
+		attrs = mergeAttributes(attrs, o.ContextAttributesFn(ctx))
+	}
+	return slices.Clip(o.applyAttributePolicy(ctx, attrs))
+}
+
+// userResultEventAttributes returns attributes of SpanResultEventAttributesFn
+// and the Result's ResultEventAttributer with the AttributePolicy applied. ok
+// is false if there are no such sources.
+func (o Options[T]) userResultEventAttributes(ctx context.Context,
+	sentCmd hooks.SentCmd[T], recvResult hooks.ReceivedResult) (
+	attrs []attribute.KeyValue, ok bool) {
+	if o.SpanResultEventAttributesFn != nil {
+		attrs, ok = o.SpanResultEventAttributesFn(sentCmd, recvResult), true
+	}
+	if attributer, is := recvResult.Result.(ResultEventAttributer); is {
+		attrs, ok = mergeAttributes(attrs, attributer.ResultEventAttributes()), true
+	}
+	return slices.Clip(o.applyAttributePolicy(ctx, attrs)), ok
+}
+
+// userCmdMetricAttributes returns attributes of CmdMetricAttributesFn and the
+// Command's MetricAttributer with the AttributePolicy applied.
+func (o Options[T]) userCmdMetricAttributes(ctx context.Context,
+	sentCmd hooks.SentCmd[T], status semconv.CmdStreamCommandStatus,
+	elapsedTime float64) (attrs []attribute.KeyValue) {
+	if o.CmdMetricAttributesFn != nil {
+		attrs = o.CmdMetricAttributesFn(sentCmd, status, elapsedTime)
+	}
+	if cmdAttrs := cmdMetricAttributes(sentCmd.Cmd); len(cmdAttrs) > 0 {
+		attrs = mergeAttributes(attrs, cmdAttrs)
+	}
+	return slices.Clip(o.applyAttributePolicy(ctx, attrs))
+}
+
+// userResultMetricAttributes returns attributes of ResultMetricAttributesFn and
+// the Command's MetricAttributer with the AttributePolicy applied.
+func (o Options[T]) userResultMetricAttributes(ctx context.Context,
+	sentCmd hooks.SentCmd[T], recvResult hooks.ReceivedResult,
+	elapsedTime float64) (attrs []attribute.KeyValue) {
+	if o.ResultMetricAttributesFn != nil {
+		attrs = o.ResultMetricAttributesFn(sentCmd, recvResult, elapsedTime)
+	}
+	if cmdAttrs := cmdMetricAttributes(sentCmd.Cmd); len(cmdAttrs) > 0 {
+		attrs = mergeAttributes(attrs, cmdAttrs)
+	}
+	return slices.Clip(o.applyAttributePolicy(ctx, attrs))
+}
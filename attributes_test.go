@@ -0,0 +1,73 @@
+package otelcmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cmd-stream/cmd-stream-go/sender/hooks"
+	asserterror "github.com/ymz-ncnk/assert/error"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestAttributes(t *testing.T) {
+	t.Run("SpanAttributesFns should be merged, the last value wins",
+		func(t *testing.T) {
+			o := Options[any]{}
+			Apply([]SetOption[any]{
+				WithSpanAttributesFn(StaticSpanAttributes[any](
+					attribute.String("platform", "p1"),
+					attribute.String("team", "t1"),
+				)),
+				WithSpanAttributesFn(StaticSpanAttributes[any](
+					attribute.String("team", "t2"),
+					attribute.String("app", "a1"),
+				)),
+			}, &o)
+			attrs := o.SpanAttributesFn(nil, hooks.SentCmd[any]{})
+			asserterror.EqualDeep(t, attrs, []attribute.KeyValue{
+				attribute.String("platform", "p1"),
+				attribute.String("team", "t2"),
+				attribute.String("app", "a1"),
+			})
+		})
+
+	t.Run("Context attributes should be added to span attributes",
+		func(t *testing.T) {
+			var (
+				o   = Options[any]{}
+				ctx = ContextWithAttributes(context.Background(),
+					attribute.String("tenant", "t1"))
+			)
+			ctx = ContextWithAttributes(ctx, attribute.String("tenant", "t2"))
+			Apply([]SetOption[any]{
+				WithSpanAttributesFn(StaticSpanAttributes[any](
+					attribute.String("tenant", "t0"),
+					attribute.String("app", "a1"),
+				)),
+				WithContextAttributesFn[any](ContextAttributes),
+			}, &o)
+			attrs := o.userSpanAttributes(ctx, nil, hooks.SentCmd[any]{})
+			asserterror.EqualDeep(t, attrs, []attribute.KeyValue{
+				attribute.String("tenant", "t2"),
+				attribute.String("app", "a1"),
+			})
+		})
+
+	t.Run("Appending to user attributes should not change the user's slice",
+		func(t *testing.T) {
+			var (
+				o         = Options[any]{}
+				userAttrs = make([]attribute.KeyValue, 1, 2)
+			)
+			userAttrs[0] = attribute.String("app", "a1")
+			Apply([]SetOption[any]{
+				WithSpanAttributesFn(StaticSpanAttributes[any](userAttrs...)),
+			}, &o)
+			attrs := o.userSpanAttributes(context.Background(), nil,
+				hooks.SentCmd[any]{})
+			_ = append(attrs, attribute.String("rpc.method", "Cmd"))
+			asserterror.EqualDeep(t, userAttrs[:2], []attribute.KeyValue{
+				attribute.String("app", "a1"), {},
+			})
+		})
+}
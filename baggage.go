@@ -0,0 +1,130 @@
+package otelcmd
+
+import (
+	"context"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/cmd-stream/cmd-stream-go/core"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// DefaultBaggageLimits follows the W3C Baggage limits for the number of
+// members and the total size.
+var DefaultBaggageLimits = BaggageLimits{
+	MaxMembers:  180,
+	MaxBytes:    8192,
+	MaxValueLen: 256,
+}
+
+// BaggageLimits defines baggage size limits. A zero value means no limit.
+//
+// MaxMembers and MaxBytes restrict the baggage injected on the client side,
+// members that would exceed them are dropped. MaxValueLen restricts the length,
+// in bytes, of a baggage value copied onto an attribute, longer values are
+// truncated on a rune boundary.
+type BaggageLimits struct {
+	MaxMembers  int
+	MaxBytes    int
+	MaxValueLen int
+}
+
+func (l BaggageLimits) allow(bag baggage.Baggage) bool {
+	if l.MaxMembers > 0 && bag.Len() > l.MaxMembers {
+		return false
+	}
+	if l.MaxBytes > 0 && len(bag.String()) > l.MaxBytes {
+		return false
+	}
+	return true
+}
+
+func (l BaggageLimits) truncate(value string) string {
+	if l.MaxValueLen > 0 {
+		return truncateString(value, l.MaxValueLen)
+	}
+	return value
+}
+
+// truncateString cuts the string to at most maxLen bytes without splitting a
+// UTF-8 encoded rune.
+func truncateString(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	for maxLen > 0 && !utf8.RuneStart(s[maxLen]) {
+		maxLen--
+	}
+	return s[:maxLen]
+}
+
+// injectBaggage adds members returned by BaggageFn to the baggage of the
+// context.
+func (o Options[T]) injectBaggage(ctx context.Context,
+	cmd core.Cmd[T]) context.Context {
+	if o.BaggageFn == nil {
+		return ctx
+	}
+	members := o.BaggageFn(cmd)
+	if len(members) == 0 {
+		return ctx
+	}
+	keys := make([]string, 0, len(members))
+	for key := range members {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	bag := baggage.FromContext(ctx)
+	for _, key := range keys {
+		member, err := baggage.NewMemberRaw(key, members[key])
+		if err != nil {
+			otel.Handle(err)
+			continue
+		}
+		newBag, err := bag.SetMember(member)
+		if err != nil {
+			otel.Handle(err)
+			continue
+		}
+		if !o.BaggageLimits.allow(newBag) {
+			continue
+		}
+		bag = newBag
+	}
+	return baggage.ContextWithBaggage(ctx, bag)
+}
+
+// baggageAttributes returns attributes for the allow-listed baggage members
+// found in the context.
+func (o Options[T]) baggageAttributes(ctx context.Context) (
+	attrs []attribute.KeyValue) {
+	if len(o.BaggageAttributeKeys) == 0 {
+		return
+	}
+	bag := baggage.FromContext(ctx)
+	if bag.Len() == 0 {
+		return
+	}
+	for _, key := range o.BaggageAttributeKeys {
+		member := bag.Member(key)
+		if member.Key() == "" {
+			continue
+		}
+		attrs = append(attrs,
+			attribute.String(key, o.BaggageLimits.truncate(member.Value())))
+	}
+	return
+}
+
+// baggageMetricAttributes returns baggage attributes if they are enabled for
+// metrics.
+func (o Options[T]) baggageMetricAttributes(ctx context.Context) (
+	attrs []attribute.KeyValue) {
+	if !o.BaggageMetricAttributes {
+		return
+	}
+	return o.baggageAttributes(ctx)
+}
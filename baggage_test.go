@@ -0,0 +1,73 @@
+package otelcmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cmd-stream/cmd-stream-go/core"
+	cmock "github.com/cmd-stream/cmd-stream-go/test/mock"
+	asserterror "github.com/ymz-ncnk/assert/error"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+func TestBaggage(t *testing.T) {
+	t.Run("BaggageFn members should be added to the context baggage",
+		func(t *testing.T) {
+			var (
+				o = Options[any]{
+					BaggageFn: func(cmd core.Cmd[any]) map[string]string {
+						return map[string]string{"tenant": "t1", "origin": "web"}
+					},
+				}
+				ctx = o.injectBaggage(context.Background(), cmock.NewCmd[any]())
+				bag = baggage.FromContext(ctx)
+			)
+			asserterror.Equal(t, bag.Len(), 2)
+			asserterror.Equal(t, bag.Member("tenant").Value(), "t1")
+			asserterror.Equal(t, bag.Member("origin").Value(), "web")
+		})
+
+	t.Run("Members exceeding the limits should be dropped", func(t *testing.T) {
+		var (
+			o = Options[any]{
+				BaggageFn: func(cmd core.Cmd[any]) map[string]string {
+					return map[string]string{"a": "1", "b": "2", "c": "3"}
+				},
+				BaggageLimits: BaggageLimits{MaxMembers: 2},
+			}
+			ctx = o.injectBaggage(context.Background(), cmock.NewCmd[any]())
+			bag = baggage.FromContext(ctx)
+		)
+		asserterror.Equal(t, bag.Len(), 2)
+		asserterror.Equal(t, bag.Member("c").Key(), "")
+	})
+
+	t.Run("Only allow-listed members should become attributes", func(t *testing.T) {
+		var (
+			o = Options[any]{
+				BaggageAttributeKeys: []string{"tenant", "missing"},
+				BaggageLimits:        BaggageLimits{MaxValueLen: 3},
+			}
+			m1, _    = baggage.NewMemberRaw("tenant", "tenant-1")
+			m2, _    = baggage.NewMemberRaw("secret", "value")
+			bag, _   = baggage.New(m1, m2)
+			ctx      = baggage.ContextWithBaggage(context.Background(), bag)
+			wantAttr = []attribute.KeyValue{attribute.String("tenant", "ten")}
+		)
+		asserterror.EqualDeep(t, o.baggageAttributes(ctx), wantAttr)
+		asserterror.EqualDeep(t, o.baggageMetricAttributes(ctx),
+			[]attribute.KeyValue(nil))
+
+		o.BaggageMetricAttributes = true
+		asserterror.EqualDeep(t, o.baggageMetricAttributes(ctx), wantAttr)
+	})
+
+	t.Run("Values should be truncated on a rune boundary", func(t *testing.T) {
+		l := BaggageLimits{MaxValueLen: 4}
+		asserterror.Equal(t, l.truncate("абв"), "аб")
+		asserterror.Equal(t, l.truncate("aбв"), "aб")
+		asserterror.Equal(t, l.truncate("ab"), "ab")
+		asserterror.Equal(t, BaggageLimits{MaxValueLen: 1}.truncate("б"), "")
+	})
+}
@@ -0,0 +1,74 @@
+package otelcmd
+
+import (
+	"context"
+	"testing"
+
+	internal_semconv "github.com/cmd-stream/otelcmd-stream-go/internal/semconv"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"github.com/cmd-stream/otelcmd-stream-go/test/mock"
+	asserterror "github.com/ymz-ncnk/assert/error"
+	"github.com/ymz-ncnk/mok"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+func TestCardinalityLimiter(t *testing.T) {
+	t.Run("Values exceeding the limit should be replaced with _OTHER",
+		func(t *testing.T) {
+			var (
+				counter = mock.NewInt64Counter().RegisterAdd(
+					func(ctx context.Context, incr int64, options ...metric.AddOption) {
+						config := metric.NewAddConfig(options)
+						asserterror.EqualDeep(t, config.Attributes(), attribute.NewSet(
+							semconv.CmdStreamAttributeKeyKey.String(
+								string(semconv.CmdStreamCommandTypeKey)),
+						))
+					},
+				)
+				meter = mock.NewMeter().RegisterInt64Counter(
+					func(name string, options ...metric.Int64CounterOption) (
+						metric.Int64Counter, error) {
+						asserterror.Equal(t, name,
+							semconv.CmdStreamAttributeCardinalityOverflowCountName)
+						return counter, nil
+					},
+				)
+				o = Options[any]{Meter: meter}
+			)
+			WithCardinalityLimit[any](semconv.CmdStreamCommandTypeKey, 2)(&o)
+			Apply(nil, &o)
+
+			for _, value := range []string{"Cmd1", "Cmd2", "Cmd1", "Cmd3"} {
+				attrs := []attribute.KeyValue{
+					semconv.CmdStreamCommandTypeKey.String(value),
+					semconv.CmdStreamCommandStatusKey.String(value),
+				}
+				o.cardinalityLimiter.Limit(context.Background(), attrs)
+
+				want := value
+				if value == "Cmd3" {
+					want = internal_semconv.OtherValue
+				}
+				asserterror.EqualDeep(t, attrs, []attribute.KeyValue{
+					semconv.CmdStreamCommandTypeKey.String(want),
+					semconv.CmdStreamCommandStatusKey.String(value),
+				})
+			}
+			asserterror.EqualDeep(t, mok.CheckCalls([]*mok.Mock{meter.Mock,
+				counter.Mock}), mok.EmptyInfomap)
+		})
+
+	t.Run("Nil limiter should not change attributes", func(t *testing.T) {
+		var (
+			limiter *internal_semconv.CardinalityLimiter
+			attrs   = []attribute.KeyValue{
+				semconv.CmdStreamCommandTypeKey.String("Cmd"),
+			}
+		)
+		limiter.Limit(context.Background(), attrs)
+		asserterror.EqualDeep(t, attrs, []attribute.KeyValue{
+			semconv.CmdStreamCommandTypeKey.String("Cmd"),
+		})
+	})
+}
@@ -0,0 +1,153 @@
+package otelcmd
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cmd-stream/cmd-stream-go/core"
+	"github.com/cmd-stream/cmd-stream-go/sender/hooks"
+	internal_semconv "github.com/cmd-stream/otelcmd-stream-go/internal/semconv"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CircuitBreakerStateFn returns the current state of the circuit breaker.
+type CircuitBreakerStateFn func() semconv.CmdStreamCircuitBreakerState
+
+// CircuitBreakerStater can be implemented by a hooks.CircuitBreaker to report
+// its state.
+type CircuitBreakerStater interface {
+	State() semconv.CmdStreamCircuitBreakerState
+}
+
+// NewCircuitBreakerHooksFactory creates a new CircuitBreakerHooksFactory.
+//
+// stateFn may be nil, then the state is taken from the circuit breaker if it
+// implements the CircuitBreakerStater interface.
+func NewCircuitBreakerHooksFactory[T any](cb hooks.CircuitBreaker,
+	factory HooksFactory[T], stateFn CircuitBreakerStateFn,
+) CircuitBreakerHooksFactory[T] {
+	if stater, ok := cb.(CircuitBreakerStater); ok && stateFn == nil {
+		stateFn = stater.State
+	}
+	f := CircuitBreakerHooksFactory[T]{
+		cb:      cb,
+		factory: factory,
+		state:   &circuitBreakerState{fn: stateFn},
+	}
+	if stateFn != nil {
+		f.state.last = stateFn()
+		internal_semconv.RegisterCircuitBreakerState(factory.options.Meter,
+			stateFn)
+	}
+	return f
+}
+
+// CircuitBreakerHooksFactory is like hooks.CircuitBreakerHooksFactory, but
+// Commands rejected by the circuit breaker are recorded by the OpenTelemetry
+// Hooks with the REJECTED status. If the state of the circuit breaker is
+// known, it is reported by the cmd-stream.client.circuit_breaker.state gauge,
+// and every state transition adds an event to the span of the Command that
+// caused it.
+type CircuitBreakerHooksFactory[T any] struct {
+	cb      hooks.CircuitBreaker
+	factory HooksFactory[T]
+	state   *circuitBreakerState
+}
+
+func (f CircuitBreakerHooksFactory[T]) New() hooks.Hooks[T] {
+	return CircuitBreakerHooks[T]{
+		cb:    f.cb,
+		hooks: f.factory.New().(*Hooks[T]),
+		state: f.state,
+	}
+}
+
+// CircuitBreakerHooks is created by the CircuitBreakerHooksFactory.
+type CircuitBreakerHooks[T any] struct {
+	cb    hooks.CircuitBreaker
+	hooks *Hooks[T]
+	state *circuitBreakerState
+}
+
+func (h CircuitBreakerHooks[T]) BeforeSend(ctx context.Context,
+	cmd core.Cmd[T]) (context.Context, error) {
+	allowed := h.cb.Allow()
+	prev, curr, changed := h.state.check()
+	if !allowed {
+		h.hooks.reject(ctx, cmd, hooks.ErrNotAllowed, func(span trace.Span) {
+			if changed {
+				addStateChangeEvent(span, prev, curr)
+			}
+		})
+		return ctx, hooks.ErrNotAllowed
+	}
+	ctx, err := h.hooks.BeforeSend(ctx, cmd)
+	if changed {
+		h.hooks.withSpan(ctx, func(span trace.Span) {
+			addStateChangeEvent(span, prev, curr)
+		})
+	}
+	return ctx, err
+}
+
+func (h CircuitBreakerHooks[T]) OnError(ctx context.Context,
+	sentCmd hooks.SentCmd[T], err error) {
+	h.cb.Fail()
+	h.checkState(ctx)
+	h.hooks.OnError(ctx, sentCmd, err)
+}
+
+func (h CircuitBreakerHooks[T]) OnResult(ctx context.Context,
+	sentCmd hooks.SentCmd[T], recvResult hooks.ReceivedResult, err error) {
+	h.cb.Success()
+	h.checkState(ctx)
+	h.hooks.OnResult(ctx, sentCmd, recvResult, err)
+}
+
+func (h CircuitBreakerHooks[T]) OnTimeout(ctx context.Context,
+	sentCmd hooks.SentCmd[T], err error) {
+	h.cb.Fail()
+	h.checkState(ctx)
+	h.hooks.OnTimeout(ctx, sentCmd, err)
+}
+
+func (h CircuitBreakerHooks[T]) checkState(ctx context.Context) {
+	if prev, curr, changed := h.state.check(); changed {
+		h.hooks.withSpan(ctx, func(span trace.Span) {
+			addStateChangeEvent(span, prev, curr)
+		})
+	}
+}
+
+// circuitBreakerState tracks the last observed state of the circuit breaker,
+// shared by all CircuitBreakerHooks of the factory.
+type circuitBreakerState struct {
+	fn   CircuitBreakerStateFn
+	mu   sync.Mutex
+	last semconv.CmdStreamCircuitBreakerState
+}
+
+// check reports whether the state has changed since the last check.
+func (s *circuitBreakerState) check() (prev,
+	curr semconv.CmdStreamCircuitBreakerState, changed bool) {
+	if s.fn == nil {
+		return
+	}
+	curr = s.fn()
+	s.mu.Lock()
+	prev = s.last
+	s.last = curr
+	s.mu.Unlock()
+	return prev, curr, prev != curr
+}
+
+func addStateChangeEvent(span trace.Span, prev,
+	curr semconv.CmdStreamCircuitBreakerState) {
+	span.AddEvent(internal_semconv.CircuitBreakerStateChangeEventName,
+		trace.WithAttributes(
+			semconv.CmdStreamCircuitBreakerPreviousStateKey.String(string(prev)),
+			semconv.CmdStreamCircuitBreakerStateKey.String(string(curr)),
+		),
+	)
+}
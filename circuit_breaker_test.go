@@ -0,0 +1,192 @@
+package otelcmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cmd-stream/cmd-stream-go/sender/hooks"
+	cmock "github.com/cmd-stream/cmd-stream-go/test/mock"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"github.com/cmd-stream/otelcmd-stream-go/test/mock"
+	asserterror "github.com/ymz-ncnk/assert/error"
+	"github.com/ymz-ncnk/mok"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestCircuitBreakerHooks(t *testing.T) {
+	t.Run("Rejected Command should be recorded with the REJECTED status",
+		func(t *testing.T) {
+			var (
+				cb    = rejectingCircuitBreaker{}
+				cmd   = cmock.NewCmd[any]()
+				span  = mock.NewSpan()
+				calls = 0
+			)
+			span.RegisterSetAttributes(
+				func(attrs ...attribute.KeyValue) { calls++ },
+			).RegisterSetAttributes(
+				func(attrs ...attribute.KeyValue) { calls++ },
+			).RegisterSetStatus(
+				func(code codes.Code, description string) {
+					asserterror.Equal(t, code, codes.Error)
+					asserterror.Equal(t, description, hooks.ErrNotAllowed.Error())
+				},
+			).RegisterEnd(
+				func(options ...trace.SpanEndOption) {},
+			)
+			var (
+				tracer = mock.NewTracer().RegisterStart(
+					func(ctx context.Context, spanName string,
+						opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+						return ctx, span
+					},
+				)
+				tracerProvider = mock.NewTracerProvider().RegisterTracer(
+					func(name string, options ...trace.TracerOption) trace.Tracer {
+						return tracer
+					},
+				)
+				meterProvider = mock.NewMeterProvider()
+				vars          = mockClientMeterProvider(meterProvider, t)
+				statusAttr    = semconv.CmdStreamCommandStatusKey.String(
+					string(semconv.Rejected))
+			)
+			vars.cmdInt64Counter.RegisterAdd(
+				func(ctx context.Context, incr int64, options ...metric.AddOption) {
+					attrs := metric.NewAddConfig(options).Attributes()
+					v, ok := attrs.Value(statusAttr.Key)
+					asserterror.Equal(t, ok, true)
+					asserterror.Equal(t, v, statusAttr.Value)
+				},
+			)
+			vars.cmdInt64Histogram.RegisterRecord(
+				func(ctx context.Context, incr int64, options ...metric.RecordOption) {},
+			)
+			vars.cmdFloat64Histogram.RegisterRecord(
+				func(ctx context.Context, incr float64,
+					options ...metric.RecordOption) {
+				},
+			)
+			factory := NewCircuitBreakerHooksFactory[any](cb,
+				NewHooksFactory[any](
+					WithTracerProvider[any](tracerProvider),
+					WithMeterProvider[any](meterProvider),
+				), nil)
+			_, err := factory.New().BeforeSend(context.Background(), cmd)
+			asserterror.EqualError(t, err, hooks.ErrNotAllowed)
+			asserterror.Equal(t, calls, 2)
+			asserterror.EqualDeep(t, mok.CheckCalls([]*mok.Mock{span.Mock,
+				vars.cmdInt64Counter.Mock, vars.cmdInt64Histogram.Mock,
+				vars.cmdFloat64Histogram.Mock}), mok.EmptyInfomap)
+		})
+
+	t.Run("State transition should add an event to the span",
+		func(t *testing.T) {
+			var (
+				cb = &testCircuitBreaker{allow: true, state: semconv.Closed,
+					failState: semconv.Open}
+				span = mock.NewSpan().RegisterAddEvent(
+					func(name string, options ...trace.EventOption) {
+						asserterror.Equal(t, name, "circuit_breaker_state_change")
+						config := trace.NewEventConfig(options...)
+						asserterror.EqualDeep(t, config.Attributes(),
+							[]attribute.KeyValue{
+								semconv.CmdStreamCircuitBreakerPreviousStateKey.String("CLOSED"),
+								semconv.CmdStreamCircuitBreakerStateKey.String("OPEN"),
+							})
+					},
+				).RegisterSetAttributes(
+					func(attrs ...attribute.KeyValue) {},
+				).RegisterSetAttributes(
+					func(attrs ...attribute.KeyValue) {},
+				).RegisterSetStatus(
+					func(code codes.Code, description string) {},
+				).RegisterEnd(
+					func(options ...trace.SpanEndOption) {},
+				)
+				factory = NewCircuitBreakerHooksFactory[any](cb,
+					NewHooksFactory[any](
+						WithMeterProvider[any](noop.NewMeterProvider()),
+					), nil)
+				h = factory.New().(CircuitBreakerHooks[any])
+			)
+			h.hooks.span = span
+			h.hooks.state = hooksStarted
+			h.OnError(context.Background(),
+				hooks.SentCmd[any]{Cmd: cmock.NewCmd[any]()}, hooks.ErrNotAllowed)
+			asserterror.EqualDeep(t, mok.CheckCalls([]*mok.Mock{span.Mock}),
+				mok.EmptyInfomap)
+		})
+
+	t.Run("State gauge should observe the state", func(t *testing.T) {
+		var (
+			cb       = &testCircuitBreaker{state: semconv.HalfOpen}
+			observed int64
+			meter    = mockClientMeter(t).RegisterInt64ObservableGauge(
+				func(name string, options ...metric.Int64ObservableGaugeOption) (
+					metric.Int64ObservableGauge, error) {
+					asserterror.Equal(t, name,
+						semconv.CmdStreamClientCircuitBreakerStateName)
+					config := metric.NewInt64ObservableGaugeConfig(options...)
+					for _, callback := range config.Callbacks() {
+						callback(context.Background(), testInt64Observer{value: &observed})
+					}
+					return nil, nil
+				},
+			)
+			meterProvider = mock.NewMeterProvider().RegisterMeter(
+				func(name string, options ...metric.MeterOption) metric.Meter {
+					return meter
+				},
+			)
+		)
+		NewCircuitBreakerHooksFactory[any](cb, NewHooksFactory[any](
+			WithMeterProvider[any](meterProvider),
+		), nil)
+		asserterror.Equal(t, observed, 1)
+		asserterror.EqualDeep(t, mok.CheckCalls([]*mok.Mock{meter.Mock}),
+			mok.EmptyInfomap)
+	})
+}
+
+type testCircuitBreaker struct {
+	allow     bool
+	state     semconv.CmdStreamCircuitBreakerState
+	failState semconv.CmdStreamCircuitBreakerState
+}
+
+func (cb *testCircuitBreaker) Allow() bool { return cb.allow }
+
+func (cb *testCircuitBreaker) Fail() {
+	if cb.failState != "" {
+		cb.state = cb.failState
+	}
+}
+
+func (cb *testCircuitBreaker) Success() {}
+
+func (cb *testCircuitBreaker) State() semconv.CmdStreamCircuitBreakerState {
+	return cb.state
+}
+
+// rejectingCircuitBreaker doesn't report its state.
+type rejectingCircuitBreaker struct{}
+
+func (rejectingCircuitBreaker) Allow() bool { return false }
+
+func (rejectingCircuitBreaker) Fail() {}
+
+func (rejectingCircuitBreaker) Success() {}
+
+type testInt64Observer struct {
+	metric.Int64Observer
+	value *int64
+}
+
+func (o testInt64Observer) Observe(value int64, options ...metric.ObserveOption) {
+	*o.value = value
+}
@@ -0,0 +1,469 @@
+package otelcmd
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	cmdstream "github.com/cmd-stream/cmd-stream-go"
+	cln "github.com/cmd-stream/cmd-stream-go/client"
+	"github.com/cmd-stream/cmd-stream-go/core"
+	ccln "github.com/cmd-stream/cmd-stream-go/core/cln"
+	dlgt "github.com/cmd-stream/cmd-stream-go/delegate"
+	dcln "github.com/cmd-stream/cmd-stream-go/delegate/cln"
+	grp "github.com/cmd-stream/cmd-stream-go/group"
+	tcln "github.com/cmd-stream/cmd-stream-go/transport/cln"
+	internal_semconv "github.com/cmd-stream/otelcmd-stream-go/internal/semconv"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// BackoffFn returns the delay before the specified reconnect attempt, attempt
+// starts from 1.
+type BackoffFn func(attempt int) time.Duration
+
+// ExponentialBackoff returns a BackoffFn with no delay before the first
+// attempt, then a delay that starts from base and doubles up to max.
+func ExponentialBackoff(base, max time.Duration) BackoffFn {
+	return func(attempt int) time.Duration {
+		if attempt <= 1 {
+			return 0
+		}
+		delay := base
+		for i := 2; i < attempt && delay < max; i++ {
+			delay *= 2
+		}
+		return min(delay, max)
+	}
+}
+
+// ClientOptions configures NewReconnectClient and NewGroup.
+type ClientOptions[T any] struct {
+	TracerProvider   trace.TracerProvider
+	MeterProvider    metric.MeterProvider
+	SemconvStability semconv.Stability
+	ReconnectBackoff BackoffFn
+
+	RecordErrorFn      RecordErrorFn
+	ErrorDescriptionFn ErrorDescriptionFn
+
+	Client []cln.SetOption
+	Group  []grp.SetOption[T]
+}
+
+type SetClientOption[T any] func(o *ClientOptions[T])
+
+// WithClientTracerProvider sets the OpenTelemetry TracerProvider for
+// connection spans.
+func WithClientTracerProvider[T any](tp trace.TracerProvider) SetClientOption[T] {
+	return func(o *ClientOptions[T]) {
+		o.TracerProvider = tp
+	}
+}
+
+// WithClientMeterProvider sets the OpenTelemetry MeterProvider for connection
+// metrics.
+func WithClientMeterProvider[T any](mp metric.MeterProvider) SetClientOption[T] {
+	return func(o *ClientOptions[T]) {
+		o.MeterProvider = mp
+	}
+}
+
+// WithClientSemconvStability sets which address attributes are added to
+// connection spans.
+func WithClientSemconvStability[T any](stability semconv.Stability) SetClientOption[T] {
+	return func(o *ClientOptions[T]) {
+		o.SemconvStability = stability
+	}
+}
+
+// WithReconnectBackoff sets the delay between reconnect attempts.
+//
+// Note that this changes the behavior of the cmd-stream client, which
+// reconnects without delay: the delay is slept in the ConnFactory before each
+// reconnect attempt, so the reconnecting goroutine is blocked during it.
+func WithReconnectBackoff[T any](fn BackoffFn) SetClientOption[T] {
+	return func(o *ClientOptions[T]) {
+		o.ReconnectBackoff = fn
+	}
+}
+
+// WithClientRecordErrorFn sets the function that decides which connection
+// errors are recorded as exception events, see WithRecordErrorFn.
+func WithClientRecordErrorFn[T any](fn RecordErrorFn) SetClientOption[T] {
+	return func(o *ClientOptions[T]) {
+		o.RecordErrorFn = fn
+	}
+}
+
+// WithClientErrorDescriptionFn sets the function that describes connection
+// errors in span statuses and exception events, see WithErrorDescriptionFn.
+func WithClientErrorDescriptionFn[T any](fn ErrorDescriptionFn) SetClientOption[T] {
+	return func(o *ClientOptions[T]) {
+		o.ErrorDescriptionFn = fn
+	}
+}
+
+// WithClient sets options of the cmd-stream client.
+func WithClient[T any](ops ...cln.SetOption) SetClientOption[T] {
+	return func(o *ClientOptions[T]) {
+		o.Client = ops
+	}
+}
+
+// WithGroup sets options of the cmd-stream group, used by NewGroup only.
+func WithGroup[T any](ops ...grp.SetOption[T]) SetClientOption[T] {
+	return func(o *ClientOptions[T]) {
+		o.Group = ops
+	}
+}
+
+// NewReconnectClient creates a client like cmdstream.NewReconnectClient does,
+// adding connection, reconnect and keepalive instrumentation.
+func NewReconnectClient[T any](codec cln.Codec[T], factory cln.ConnFactory,
+	ops ...SetClientOption[T],
+) (client *ccln.Client[T], err error) {
+	var (
+		co    = applyClientOptions(ops)
+		instr = newConnInstruments(co)
+	)
+	return newReconnectClient(codec, instr.connFactory(factory, true), instr,
+		co.Client)
+}
+
+// NewGroup creates a group like cmdstream.NewGroup does, each client of the
+// group is instrumented separately. Group options are set with the WithGroup option,
+// client options - with the WithClient option and grp.WithClient, both are
+// applied, grp.WithClient first.
+//
+// Each client reports the remote address of its own connection, so with
+// DispatchStrategyFactory client spans get the address of the connection the
+// Command was sent through.
+//
+// The returned group can be used with the sender.New function.
+func NewGroup[T any](clientsCount int, codec cln.Codec[T],
+	factory cln.ConnFactory, ops ...SetClientOption[T],
+) (group grp.Group[T], err error) {
+	if clientsCount <= 0 {
+		err = core.NewError(grp.ErrInvalidClientsCount)
+		return
+	}
+	co := applyClientOptions(ops)
+	o := grp.DefaultOptions[T]()
+	if err = grp.Apply(&o, co.Group...); err != nil {
+		return
+	}
+	var (
+		c         *ccln.Client[T]
+		clientOps = append(o.ClientOpts[:len(o.ClientOpts):len(o.ClientOpts)],
+			co.Client...)
+		clients = make([]grp.Client[T], 0, clientsCount)
+	)
+	for range clientsCount {
+		var (
+			instr       = newConnInstruments(co)
+			connFactory = NewConnFactory(instr.connFactory(factory, o.Reconnect))
+		)
+		if o.Reconnect {
+			c, err = newReconnectClient(codec, connFactory, instr, clientOps)
+		} else {
+			var conn net.Conn
+			if conn, err = connFactory.New(); err == nil {
+				c, err = newClient(codec, conn, instr, clientOps)
+			}
+		}
+		if err != nil {
+			for _, client := range clients {
+				_ = client.Close()
+			}
+			err = core.NewError(err)
+			return
+		}
+		clients = append(clients, addrClient[T]{c, connFactory})
+	}
+	group = grp.New(o.Factory.New(clients))
+	return
+}
+
+// addrClient is a group client that reports the remote address of its
+// connection.
+type addrClient[T any] struct {
+	*ccln.Client[T]
+	factory *ConnFactory
+}
+
+func (c addrClient[T]) RemoteAddr() net.Addr {
+	return c.factory.RemoteAddr()
+}
+
+func applyClientOptions[T any](ops []SetClientOption[T]) ClientOptions[T] {
+	o := ClientOptions[T]{
+		TracerProvider:   otel.GetTracerProvider(),
+		MeterProvider:    otel.GetMeterProvider(),
+		SemconvStability: semconv.StabilityFromEnv(),
+	}
+	for i := range ops {
+		if ops[i] != nil {
+			ops[i](&o)
+		}
+	}
+	return o
+}
+
+// newClient creates a client with cmdstream.NewClient. With keepalive, the
+// client is assembled from the same parts, because the Ping-Pong exchange is
+// seen only by the delegate wrapped by the dcln.KeepaliveDelegate.
+func newClient[T any](codec cln.Codec[T], conn net.Conn,
+	instr *connInstruments, ops []cln.SetOption,
+) (client *ccln.Client[T], err error) {
+	o := cln.DefaultOptions()
+	cln.Apply(&o, ops...)
+	if o.Keepalive == nil {
+		return cmdstream.NewClient(codec, conn, ops...)
+	}
+	var (
+		delegate  core.ClientDelegate[T]
+		transport = tcln.New(conn, cln.AdaptCodec(codec, o), o.Transport...)
+	)
+	if delegate, err = dcln.New(o.Info, transport, o.Delegate...); err != nil {
+		err = core.NewError(err)
+		return
+	}
+	client = newKeepaliveClient(delegate, instr, o)
+	return
+}
+
+// newReconnectClient creates a client with cmdstream.NewReconnectClient, or,
+// with keepalive, like newClient does. factory must be already instrumented by
+// instr. A reconnect still in progress when the client is done is ended with
+// the ccln.ErrClosed error.
+func newReconnectClient[T any](codec cln.Codec[T], factory cln.ConnFactory,
+	instr *connInstruments, ops []cln.SetOption,
+) (client *ccln.Client[T], err error) {
+	o := cln.DefaultOptions()
+	cln.Apply(&o, ops...)
+	if o.Keepalive == nil {
+		client, err = cmdstream.NewReconnectClient(codec, factory, ops...)
+	} else {
+		var (
+			delegate         core.ClientDelegate[T]
+			transportFactory = cln.NewTransportFactory(cln.AdaptCodec(codec, o),
+				factory, o.Transport...)
+		)
+		delegate, err = dcln.NewReconnect(o.Info, transportFactory, o.Delegate...)
+		if err != nil {
+			err = core.NewError(err)
+		} else {
+			client = newKeepaliveClient(delegate, instr, o)
+		}
+	}
+	if err != nil {
+		return
+	}
+	go func() {
+		<-client.Done()
+		instr.endReconnect(ccln.ErrClosed)
+	}()
+	return
+}
+
+// newKeepaliveClient wraps the delegate with keepaliveRTTDelegate and
+// dcln.KeepaliveDelegate, as cmdstream.NewClient does with the latter.
+func newKeepaliveClient[T any](delegate core.ClientDelegate[T],
+	instr *connInstruments, o cln.Options) *ccln.Client[T] {
+	delegate = &keepaliveRTTDelegate[T]{ClientDelegate: delegate, instr: instr}
+	return ccln.New(dcln.NewKeepalive(delegate, o.Keepalive...), o.Base...)
+}
+
+// keepaliveRTTDelegate measures the keepalive Ping-Pong round-trip time, it
+// should be wrapped by the dcln.KeepaliveDelegate.
+type keepaliveRTTDelegate[T any] struct {
+	core.ClientDelegate[T]
+	instr *connInstruments
+}
+
+func (d *keepaliveRTTDelegate[T]) Send(seq core.Seq, cmd core.Cmd[T]) (n int,
+	err error) {
+	if _, ok := cmd.(dlgt.PingCmd[T]); ok {
+		d.instr.pingTime.Store(time.Now().UnixNano())
+	}
+	return d.ClientDelegate.Send(seq, cmd)
+}
+
+func (d *keepaliveRTTDelegate[T]) Receive() (seq core.Seq, result core.Result,
+	n int, err error) {
+	seq, result, n, err = d.ClientDelegate.Receive()
+	if _, ok := result.(dlgt.PongResult); ok && err == nil {
+		if t := d.instr.pingTime.Swap(0); t != 0 {
+			rtt := time.Since(time.Unix(0, t)).Seconds()
+			d.instr.semconv.RecordKeepaliveRTT(context.Background(), rtt)
+		}
+	}
+	return
+}
+
+func (d *keepaliveRTTDelegate[T]) Unwrap() core.ClientDelegate[T] {
+	return d.ClientDelegate
+}
+
+func newConnInstruments[T any](o ClientOptions[T]) *connInstruments {
+	var (
+		meter  metric.Meter
+		tracer trace.Tracer = tracenoop.Tracer{}
+	)
+	if o.MeterProvider != nil {
+		meter = o.MeterProvider.Meter(ScopeName,
+			metric.WithInstrumentationVersion(Version()))
+	}
+	if o.TracerProvider != nil {
+		tracer = newTracer(o.TracerProvider)
+	}
+	return &connInstruments{
+		semconv:   internal_semconv.NewCmdStreamClientConnection(meter),
+		tracer:    tracer,
+		stability: o.SemconvStability,
+		backoff:   o.ReconnectBackoff,
+		errs:      errorOptions{o.RecordErrorFn, o.ErrorDescriptionFn},
+	}
+}
+
+// connInstruments holds the telemetry state of a single client.
+type connInstruments struct {
+	semconv   internal_semconv.CmdStreamClientConnection
+	tracer    trace.Tracer
+	stability semconv.Stability
+	backoff   BackoffFn
+	errs      errorOptions
+	// pingTime is the send time of the keepalive Ping in flight, in Unix
+	// nanoseconds, or 0.
+	pingTime atomic.Int64
+
+	mu sync.Mutex
+	// connected is true if the client has established a connection, so the
+	// next dial is a reconnect.
+	connected bool
+	// reconnect is the reconnect in progress, if any.
+	reconnect *reconnectState
+}
+
+// reconnectState is the state of a reconnect, which lasts from the first dial
+// after the connection was lost to the first successful one.
+type reconnectState struct {
+	ctx       context.Context
+	span      trace.Span
+	startTime time.Time
+	attempt   int
+}
+
+// connFactory returns a ConnFactory that records connection attempts. For a
+// reconnect client, reconnect is true, and all dials after the first
+// successful one are recorded as reconnect attempts.
+func (i *connInstruments) connFactory(factory cln.ConnFactory,
+	reconnect bool) cln.ConnFactory {
+	return cln.ConnFactoryFn(func() (net.Conn, error) {
+		return i.connect(factory, reconnect)
+	})
+}
+
+// connect dials the server. While reconnecting it waits for the backoff delay
+// first and records the attempt on the reconnect span.
+func (i *connInstruments) connect(factory cln.ConnFactory, reconnect bool) (
+	conn net.Conn, err error) {
+	var (
+		ctx     = context.Background()
+		delay   time.Duration
+		state   *reconnectState
+		attempt int
+	)
+	if reconnect {
+		state, attempt = i.startReconnectAttempt()
+	}
+	if state != nil {
+		ctx = state.ctx
+		if i.backoff != nil {
+			if delay = i.backoff(attempt); delay > 0 {
+				time.Sleep(delay)
+			}
+		}
+	}
+	ctx, span := i.tracer.Start(ctx, internal_semconv.ConnectSpanName,
+		trace.WithSpanKind(trace.SpanKindClient))
+	conn, err = factory.New()
+	if err != nil {
+		span.SetAttributes(internal_semconv.ErrAttrs(err)...)
+		i.errs.recordError(span, err)
+		span.SetStatus(codes.Error, i.errs.errorDescription(err))
+	} else {
+		span.SetAttributes(internal_semconv.ClientAddrAttrs(conn.RemoteAddr(),
+			i.stability)...)
+	}
+	span.End()
+
+	i.semconv.RecordAttempt(ctx, err)
+	if state != nil {
+		i.semconv.RecordReconnectAttempt(ctx, delay.Seconds(), err)
+		attrs := []attribute.KeyValue{
+			semconv.CmdStreamReconnectAttemptKey.Int(attempt),
+		}
+		state.span.AddEvent(internal_semconv.ReconnectAttemptEventName,
+			trace.WithAttributes(append(attrs, internal_semconv.ErrAttrs(err)...)...))
+	}
+	if reconnect && err == nil {
+		i.mu.Lock()
+		i.connected = true
+		i.mu.Unlock()
+		i.endReconnect(nil)
+	}
+	return
+}
+
+// startReconnectAttempt starts the reconnect if the client was connected, and
+// returns the reconnect in progress with the number of the attempt, state is
+// nil for the first connection.
+func (i *connInstruments) startReconnectAttempt() (state *reconnectState,
+	attempt int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.connected {
+		i.connected = false
+		i.pingTime.Store(0)
+		state = &reconnectState{startTime: time.Now()}
+		state.ctx, state.span = i.tracer.Start(context.Background(),
+			internal_semconv.ReconnectSpanName)
+		i.reconnect = state
+	}
+	if i.reconnect == nil {
+		return
+	}
+	i.reconnect.attempt++
+	return i.reconnect, i.reconnect.attempt
+}
+
+// endReconnect ends the reconnect in progress, if any, and records the time
+// spent disconnected, err is nil if the client has reconnected.
+func (i *connInstruments) endReconnect(err error) {
+	i.mu.Lock()
+	state := i.reconnect
+	i.reconnect = nil
+	i.mu.Unlock()
+	if state == nil {
+		return
+	}
+	i.semconv.RecordDisconnected(state.ctx,
+		time.Since(state.startTime).Seconds(), err)
+	state.span.SetAttributes(
+		semconv.CmdStreamReconnectAttemptKey.Int(state.attempt))
+	if err != nil {
+		state.span.SetAttributes(internal_semconv.ErrAttrs(err)...)
+		i.errs.recordError(state.span, err)
+		state.span.SetStatus(codes.Error, i.errs.errorDescription(err))
+	}
+	state.span.End()
+}
@@ -0,0 +1,366 @@
+package otelcmd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	cmdstream "github.com/cmd-stream/cmd-stream-go"
+	cln "github.com/cmd-stream/cmd-stream-go/client"
+	"github.com/cmd-stream/cmd-stream-go/core"
+	ccln "github.com/cmd-stream/cmd-stream-go/core/cln"
+	dlgt "github.com/cmd-stream/cmd-stream-go/delegate"
+	dcln "github.com/cmd-stream/cmd-stream-go/delegate/cln"
+	grp "github.com/cmd-stream/cmd-stream-go/group"
+	cmock "github.com/cmd-stream/cmd-stream-go/test/mock"
+	"github.com/cmd-stream/cmd-stream-go/testkit"
+	internal_semconv "github.com/cmd-stream/otelcmd-stream-go/internal/semconv"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"github.com/cmd-stream/otelcmd-stream-go/test/mock"
+	asserterror "github.com/ymz-ncnk/assert/error"
+	"github.com/ymz-ncnk/mok"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestClient(t *testing.T) {
+	t.Run("ExponentialBackoff should double the delay up to max",
+		func(t *testing.T) {
+			backoff := ExponentialBackoff(time.Second, 5*time.Second)
+			asserterror.Equal(t, backoff(1), 0)
+			asserterror.Equal(t, backoff(2), time.Second)
+			asserterror.Equal(t, backoff(3), 2*time.Second)
+			asserterror.Equal(t, backoff(4), 4*time.Second)
+			asserterror.Equal(t, backoff(5), 5*time.Second)
+		})
+
+	t.Run("Reconnect should pass attempt numbers to the backoff",
+		func(t *testing.T) {
+			var (
+				wantErr  = errors.New("connection refused")
+				attempts []int
+				instr    = newConnInstruments(ClientOptions[any]{
+					MeterProvider: noop.NewMeterProvider(),
+					ReconnectBackoff: func(attempt int) time.Duration {
+						attempts = append(attempts, attempt)
+						return 0
+					},
+				})
+				calls   = 0
+				factory = instr.connFactory(cln.ConnFactoryFn(
+					func() (net.Conn, error) {
+						if calls++; calls == 2 {
+							return nil, wantErr
+						}
+						return cmock.NewConn().RegisterRemoteAddr(
+							func() net.Addr { return &net.TCPAddr{} },
+						), nil
+					}), true)
+			)
+			_, err := factory.New()
+			asserterror.EqualError(t, err, nil)
+			_, err = factory.New()
+			asserterror.EqualError(t, err, wantErr)
+			asserterror.Equal(t, instr.reconnect != nil, true)
+			_, err = factory.New()
+			asserterror.EqualError(t, err, nil)
+			asserterror.EqualDeep(t, attempts, []int{1, 2})
+			asserterror.Equal(t, instr.reconnect == nil, true)
+		})
+
+	t.Run("Closed client should end the pending reconnect", func(t *testing.T) {
+		var (
+			wantErr = errors.New("connection refused")
+			instr   = newConnInstruments(ClientOptions[any]{
+				MeterProvider: noop.NewMeterProvider(),
+			})
+			calls   = 0
+			factory = instr.connFactory(cln.ConnFactoryFn(
+				func() (net.Conn, error) {
+					if calls++; calls > 1 {
+						return nil, wantErr
+					}
+					return cmock.NewConn().RegisterRemoteAddr(
+						func() net.Addr { return &net.TCPAddr{} },
+					), nil
+				}), true)
+		)
+		factory.New()
+		factory.New()
+		asserterror.Equal(t, instr.reconnect != nil, true)
+		instr.endReconnect(ccln.ErrClosed)
+		asserterror.Equal(t, instr.reconnect == nil, true)
+	})
+
+	t.Run("Initial connection should not be a reconnect attempt",
+		func(t *testing.T) {
+			var (
+				instr = newConnInstruments(ClientOptions[any]{
+					MeterProvider: noop.NewMeterProvider(),
+					ReconnectBackoff: func(attempt int) time.Duration {
+						t.Error("unexpected backoff call")
+						return 0
+					},
+				})
+				wantErr = errors.New("connection refused")
+				factory = instr.connFactory(cln.ConnFactoryFn(
+					func() (net.Conn, error) { return nil, wantErr }), true)
+			)
+			_, err := factory.New()
+			asserterror.EqualError(t, err, wantErr)
+		})
+
+	t.Run("Failed connection should use ErrorDescriptionFn for the status",
+		func(t *testing.T) {
+			var (
+				wantErr = errors.New("connection refused")
+				span    = mock.NewSpan().RegisterSetAttributes(
+					func(kv ...attribute.KeyValue) {},
+				).RegisterSetStatus(
+					func(code codes.Code, description string) {
+						asserterror.Equal(t, code, codes.Error)
+						asserterror.Equal(t, description, "*errors.errorString")
+					},
+				).RegisterEnd(
+					func(options ...trace.SpanEndOption) {},
+				)
+				tracer = mock.NewTracer().RegisterStart(
+					func(ctx context.Context, spanName string,
+						opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+						return ctx, span
+					},
+				)
+				instr = newConnInstruments(ClientOptions[any]{
+					MeterProvider:      noop.NewMeterProvider(),
+					ErrorDescriptionFn: TypeErrorDescription,
+				})
+			)
+			instr.tracer = tracer
+			_, err := instr.connFactory(cln.ConnFactoryFn(
+				func() (net.Conn, error) { return nil, wantErr }), false).New()
+			asserterror.EqualError(t, err, wantErr)
+			asserterror.EqualDeep(t, mok.CheckCalls([]*mok.Mock{span.Mock,
+				tracer.Mock}), mok.EmptyInfomap)
+		})
+
+	t.Run("NewGroup with WithReconnect should record each attempt once",
+		func(t *testing.T) {
+			var (
+				listener = startTestServer(t)
+				factory  = &testConnFactory{addr: listener.Addr().String()}
+				tracer   = &spanNamesTracer{}
+				mu       sync.Mutex
+				attempts []int
+			)
+			group, err := NewGroup[testkit.Receiver](1, testkit.ClientCodec{},
+				factory,
+				WithGroup(grp.WithReconnect[testkit.Receiver]()),
+				WithClientTracerProvider[testkit.Receiver](
+					spanNamesTracerProvider{tracer: tracer}),
+				WithClientMeterProvider[testkit.Receiver](noop.NewMeterProvider()),
+				WithReconnectBackoff[testkit.Receiver](
+					func(attempt int) time.Duration {
+						mu.Lock()
+						attempts = append(attempts, attempt)
+						mu.Unlock()
+						return 0
+					}),
+			)
+			asserterror.EqualError(t, err, nil)
+			defer group.Close()
+
+			factory.lastConn().Close()
+			tracer.waitFor(t, internal_semconv.ReconnectSpanName)
+			mu.Lock()
+			asserterror.EqualDeep(t, attempts, []int{1})
+			mu.Unlock()
+			asserterror.EqualDeep(t, tracer.spanNames(), []string{
+				internal_semconv.ConnectSpanName,
+				internal_semconv.ReconnectSpanName,
+				internal_semconv.ConnectSpanName,
+			})
+		})
+
+	t.Run("Pong should record the keepalive RTT", func(t *testing.T) {
+		var (
+			rtt = mock.NewFloat64Histogram().RegisterRecord(
+				func(ctx context.Context, incr float64,
+					options ...metric.RecordOption) {
+					if incr <= 0 {
+						t.Errorf("unexpected rtt %v", incr)
+					}
+				},
+			)
+			meter = mock.NewMeter().RegisterInt64Counter(
+				func(name string, options ...metric.Int64CounterOption) (
+					metric.Int64Counter, error) {
+					return noop.Int64Counter{}, nil
+				},
+			).RegisterInt64Counter(
+				func(name string, options ...metric.Int64CounterOption) (
+					metric.Int64Counter, error) {
+					return noop.Int64Counter{}, nil
+				},
+			)
+		)
+		for range 3 {
+			meter = meter.RegisterFloat64Histogram(
+				func(name string, options ...metric.Float64HistogramOption) (
+					metric.Float64Histogram, error) {
+					if name == semconv.CmdStreamClientKeepaliveRTTName {
+						return rtt, nil
+					}
+					return noop.Float64Histogram{}, nil
+				},
+			)
+		}
+		var (
+			meterProvider = mock.NewMeterProvider().RegisterMeter(
+				func(name string, options ...metric.MeterOption) metric.Meter {
+					return meter
+				},
+			)
+			delegate = cmock.NewClientDelegate[any]().RegisterSend(
+				func(seq core.Seq, cmd core.Cmd[any]) (n int, err error) {
+					return 1, nil
+				},
+			).RegisterReceive(
+				func() (seq core.Seq, result core.Result, n int, err error) {
+					time.Sleep(time.Millisecond)
+					return 0, dlgt.PongResult{}, 1, nil
+				},
+			)
+			d = &keepaliveRTTDelegate[any]{
+				ClientDelegate: delegate,
+				instr: newConnInstruments(ClientOptions[any]{
+					MeterProvider: meterProvider,
+				}),
+			}
+		)
+		d.Send(0, dlgt.PingCmd[any]{})
+		d.Receive()
+		asserterror.EqualDeep(t, mok.CheckCalls([]*mok.Mock{meter.Mock, rtt.Mock,
+			delegate.Mock}), mok.EmptyInfomap)
+	})
+
+	t.Run("Keepalive client of NewGroup should reconnect",
+		func(t *testing.T) {
+			var (
+				listener = startTestServer(t)
+				factory  = &testConnFactory{addr: listener.Addr().String()}
+				tracer   = &spanNamesTracer{}
+			)
+			group, err := NewGroup[testkit.Receiver](1, testkit.ClientCodec{},
+				factory,
+				WithGroup(grp.WithReconnect[testkit.Receiver]()),
+				WithClient[testkit.Receiver](cln.WithKeepalive(
+					dcln.WithKeepaliveTime(10*time.Millisecond),
+					dcln.WithKeepaliveIntvl(10*time.Millisecond),
+				)),
+				WithClientTracerProvider[testkit.Receiver](
+					spanNamesTracerProvider{tracer: tracer}),
+				WithClientMeterProvider[testkit.Receiver](noop.NewMeterProvider()),
+			)
+			asserterror.EqualError(t, err, nil)
+			defer group.Close()
+
+			factory.lastConn().Close()
+			tracer.waitFor(t, internal_semconv.ReconnectSpanName)
+			asserterror.EqualDeep(t, tracer.spanNames(), []string{
+				internal_semconv.ConnectSpanName,
+				internal_semconv.ReconnectSpanName,
+				internal_semconv.ConnectSpanName,
+			})
+		})
+}
+
+func startTestServer(t *testing.T) net.Listener {
+	server, err := cmdstream.NewServer(testkit.Receiver{}, testkit.ServerCodec{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = server.Serve(listener.(*net.TCPListener))
+	}()
+	t.Cleanup(func() { _ = server.Close() })
+	return listener
+}
+
+// testConnFactory dials the address and keeps the last connection.
+type testConnFactory struct {
+	addr string
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func (f *testConnFactory) New() (conn net.Conn, err error) {
+	if conn, err = net.Dial("tcp", f.addr); err != nil {
+		return
+	}
+	f.mu.Lock()
+	f.conn = conn
+	f.mu.Unlock()
+	return
+}
+
+func (f *testConnFactory) lastConn() net.Conn {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.conn
+}
+
+type spanNamesTracerProvider struct {
+	tracenoop.TracerProvider
+	tracer *spanNamesTracer
+}
+
+func (p spanNamesTracerProvider) Tracer(name string,
+	options ...trace.TracerOption) trace.Tracer {
+	return p.tracer
+}
+
+// spanNamesTracer records names of the started spans.
+type spanNamesTracer struct {
+	tracenoop.Tracer
+	mu    sync.Mutex
+	names []string
+}
+
+func (t *spanNamesTracer) Start(ctx context.Context, spanName string,
+	opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	t.mu.Lock()
+	t.names = append(t.names, spanName)
+	t.mu.Unlock()
+	return t.Tracer.Start(ctx, spanName, opts...)
+}
+
+func (t *spanNamesTracer) spanNames() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]string(nil), t.names...)
+}
+
+// waitFor waits until a span with the specified name is started, and then a
+// little longer for the spans that follow it.
+func (t *spanNamesTracer) waitFor(tb testing.TB, spanName string) {
+	for range 100 {
+		if slices.Contains(t.spanNames(), spanName) {
+			time.Sleep(50 * time.Millisecond)
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	tb.Fatalf("span %q was not started", spanName)
+}
@@ -0,0 +1,191 @@
+package otelcmd
+
+import (
+	"context"
+	"time"
+
+	cln "github.com/cmd-stream/cmd-stream-go/client"
+	"github.com/cmd-stream/cmd-stream-go/core"
+	srv "github.com/cmd-stream/cmd-stream-go/server"
+	tspt "github.com/cmd-stream/cmd-stream-go/transport"
+	internal_semconv "github.com/cmd-stream/otelcmd-stream-go/internal/semconv"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CodecOptions configures ClientCodec and ServerCodec.
+type CodecOptions struct {
+	MeterProvider metric.MeterProvider
+	SpanEvents    bool
+}
+
+type SetCodecOption func(o *CodecOptions)
+
+// WithCodecMeterProvider sets the OpenTelemetry MeterProvider for codec
+// metrics.
+func WithCodecMeterProvider(mp metric.MeterProvider) SetCodecOption {
+	return func(o *CodecOptions) {
+		o.MeterProvider = mp
+	}
+}
+
+// WithCodecSpanEvents enables the encoded and decoded span events. Codecs
+// don't receive a context, so the span is found through the TraceCmd, and the
+// events are added only for TraceCmd Commands: "encoded" to the client span,
+// if the Hooks have the WithCodecEvents option, and "decoded" to the server
+// span.
+func WithCodecSpanEvents() SetCodecOption {
+	return func(o *CodecOptions) {
+		o.SpanEvents = true
+	}
+}
+
+// NewClientCodec creates a new ClientCodec.
+func NewClientCodec[T any](codec cln.Codec[T],
+	ops ...SetCodecOption) ClientCodec[T] {
+	o := applyCodecOptions(ops)
+	return ClientCodec[T]{
+		codec:   codec,
+		semconv: internal_semconv.NewCmdStreamCodec(codecMeter(o)),
+		options: o,
+	}
+}
+
+// ClientCodec wraps a client codec and records the cmd-stream.codec.duration,
+// .size and .errors metrics per Command and Result type.
+type ClientCodec[T any] struct {
+	codec   cln.Codec[T]
+	semconv internal_semconv.CmdStreamCodec
+	options CodecOptions
+}
+
+func (c ClientCodec[T]) Encode(cmd core.Cmd[T], w tspt.Writer) (n int,
+	err error) {
+	startTime := time.Now()
+	n, err = c.codec.Encode(cmd, w)
+	elapsedTime := time.Since(startTime).Seconds()
+	c.semconv.Record(context.Background(), semconv.Encode, cmdTypeAttr(cmd), n,
+		elapsedTime, err)
+	if c.options.SpanEvents && err == nil {
+		if state := cmdState(cmd); state != nil {
+			if h := state.hooks.Load(); h != nil {
+				h.encoded(state, n, elapsedTime)
+			}
+		}
+	}
+	return
+}
+
+func (c ClientCodec[T]) Decode(r tspt.Reader) (result core.Result, n int,
+	err error) {
+	startTime := time.Now()
+	result, n, err = c.codec.Decode(r)
+	c.semconv.Record(context.Background(), semconv.Decode,
+		resultTypeAttr(result), n, time.Since(startTime).Seconds(), err)
+	return
+}
+
+// NewServerCodec creates a new ServerCodec.
+func NewServerCodec[T any](codec srv.Codec[T],
+	ops ...SetCodecOption) ServerCodec[T] {
+	o := applyCodecOptions(ops)
+	return ServerCodec[T]{
+		codec:   codec,
+		semconv: internal_semconv.NewCmdStreamCodec(codecMeter(o)),
+		options: o,
+	}
+}
+
+// ServerCodec wraps a server codec and records the cmd-stream.codec.duration,
+// .size and .errors metrics per Command and Result type.
+type ServerCodec[T any] struct {
+	codec   srv.Codec[T]
+	semconv internal_semconv.CmdStreamCodec
+	options CodecOptions
+}
+
+func (c ServerCodec[T]) Encode(result core.Result, w tspt.Writer) (n int,
+	err error) {
+	startTime := time.Now()
+	n, err = c.codec.Encode(result, w)
+	c.semconv.Record(context.Background(), semconv.Encode,
+		resultTypeAttr(result), n, time.Since(startTime).Seconds(), err)
+	return
+}
+
+func (c ServerCodec[T]) Decode(r tspt.Reader) (cmd core.Cmd[T], n int,
+	err error) {
+	startTime := time.Now()
+	cmd, n, err = c.codec.Decode(r)
+	endTime := time.Now()
+	elapsedTime := endTime.Sub(startTime).Seconds()
+	c.semconv.Record(context.Background(), semconv.Decode, cmdTypeAttr(cmd), n,
+		elapsedTime, err)
+	if c.options.SpanEvents && err == nil {
+		if scmd, ok := cmd.(stateCmd[T]); ok {
+			cmd = scmd.withDecodeEvent(decodeEvent{
+				time:        endTime,
+				size:        n,
+				elapsedTime: elapsedTime,
+			})
+		}
+	}
+	return
+}
+
+func applyCodecOptions(ops []SetCodecOption) CodecOptions {
+	o := CodecOptions{MeterProvider: otel.GetMeterProvider()}
+	for i := range ops {
+		if ops[i] != nil {
+			ops[i](&o)
+		}
+	}
+	return o
+}
+
+func codecMeter(o CodecOptions) metric.Meter {
+	if o.MeterProvider == nil {
+		return nil
+	}
+	return o.MeterProvider.Meter(ScopeName,
+		metric.WithInstrumentationVersion(Version()))
+}
+
+func cmdTypeAttr[T any](cmd core.Cmd[T]) attribute.KeyValue {
+	if cmd == nil {
+		return attribute.KeyValue{}
+	}
+	return semconv.CmdStreamCommandTypeKey.String(internal_semconv.TypeStr(cmd))
+}
+
+func resultTypeAttr(result core.Result) attribute.KeyValue {
+	if result == nil {
+		return attribute.KeyValue{}
+	}
+	return semconv.CmdStreamResultTypeKey.String(internal_semconv.TypeStr(result))
+}
+
+type decodeEvent struct {
+	time        time.Time
+	size        int
+	elapsedTime float64
+}
+
+// addDecodedEvent adds the decoded event, recorded by the ServerCodec, to the
+// span.
+func addDecodedEvent[T any](cmd core.Cmd[T], span trace.Span) {
+	state := cmdState(cmd)
+	if state == nil || state.decoded == nil {
+		return
+	}
+	event := *state.decoded
+	state.decoded = nil
+	span.AddEvent(internal_semconv.DecodedEventName,
+		trace.WithTimestamp(event.time),
+		trace.WithAttributes(
+			internal_semconv.CodecEventAttrs(event.size, event.elapsedTime)...),
+	)
+}
@@ -0,0 +1,183 @@
+package otelcmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cmd-stream/cmd-stream-go/core"
+	cmock "github.com/cmd-stream/cmd-stream-go/test/mock"
+	tspt "github.com/cmd-stream/cmd-stream-go/transport"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"github.com/cmd-stream/otelcmd-stream-go/test/mock"
+	asserterror "github.com/ymz-ncnk/assert/error"
+	"github.com/ymz-ncnk/mok"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	otel_semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestCodec(t *testing.T) {
+	t.Run("ClientCodec should record encode metrics and the encoded event",
+		func(t *testing.T) {
+			var (
+				cmd     = NewTraceCmd[any](cmock.NewCmd[any]())
+				wantSet = attribute.NewSet(
+					semconv.CmdStreamCodecOperationKey.String(string(semconv.Encode)),
+					semconv.CmdStreamCommandTypeKey.String("Cmd[interface {}] (trace)"),
+				)
+				duration = mock.NewFloat64Histogram().RegisterRecord(
+					func(ctx context.Context, incr float64,
+						options ...metric.RecordOption) {
+						config := metric.NewRecordConfig(options)
+						asserterror.EqualDeep(t, config.Attributes(), wantSet)
+					},
+				)
+				size = mock.NewInt64Histogram().RegisterRecord(
+					func(ctx context.Context, incr int64,
+						options ...metric.RecordOption) {
+						asserterror.Equal(t, incr, 3)
+					},
+				)
+				span = mock.NewSpan().RegisterAddEvent(
+					func(name string, options ...trace.EventOption) {
+						asserterror.Equal(t, name, "encoded")
+						config := trace.NewEventConfig(options...)
+						asserterror.EqualDeep(t, config.Attributes()[0],
+							semconv.CmdStreamCommandSizeKey.Int64(3))
+					},
+				)
+				meterProvider = codecMeterProvider(duration, size,
+					mock.NewInt64Counter())
+				codec = NewClientCodec[any](clientCodecFn[any]{
+					encode: func(cmd core.Cmd[any], w tspt.Writer) (int, error) {
+						return 3, nil
+					},
+				}, WithCodecMeterProvider(meterProvider), WithCodecSpanEvents())
+			)
+			cmd.state().hooks.Store(&Hooks[any]{
+				state:    hooksStarted,
+				span:     span,
+				options:  Options[any]{CodecEvents: true},
+				cmdState: cmd.state(),
+			})
+			n, err := codec.Encode(cmd, nil)
+			asserterror.Equal(t, n, 3)
+			asserterror.EqualError(t, err, nil)
+			asserterror.EqualDeep(t, mok.CheckCalls([]*mok.Mock{duration.Mock,
+				size.Mock, span.Mock}), mok.EmptyInfomap)
+		})
+
+	t.Run("ServerCodec should record decode errors by type", func(t *testing.T) {
+		var (
+			wantErr = errors.New("decode failed")
+			wantSet = attribute.NewSet(
+				semconv.CmdStreamCodecOperationKey.String(string(semconv.Decode)),
+				otel_semconv.ErrorTypeKey.String("*errors.errorString"),
+			)
+			duration = mock.NewFloat64Histogram().RegisterRecord(
+				func(ctx context.Context, incr float64,
+					options ...metric.RecordOption) {
+					config := metric.NewRecordConfig(options)
+					asserterror.EqualDeep(t, config.Attributes(), wantSet)
+				},
+			)
+			errors = mock.NewInt64Counter().RegisterAdd(
+				func(ctx context.Context, incr int64, options ...metric.AddOption) {
+					config := metric.NewAddConfig(options)
+					asserterror.EqualDeep(t, config.Attributes(), wantSet)
+				},
+			)
+			meterProvider = codecMeterProvider(duration, mock.NewInt64Histogram(),
+				errors)
+			codec = NewServerCodec[any](serverCodecFn[any]{
+				decode: func(r tspt.Reader) (core.Cmd[any], int, error) {
+					return nil, 1, wantErr
+				},
+			}, WithCodecMeterProvider(meterProvider))
+		)
+		_, _, err := codec.Decode(nil)
+		asserterror.EqualError(t, err, wantErr)
+		asserterror.EqualDeep(t, mok.CheckCalls([]*mok.Mock{duration.Mock,
+			errors.Mock}), mok.EmptyInfomap)
+	})
+
+	t.Run("ServerCodec decoded event should be added to the server span",
+		func(t *testing.T) {
+			var (
+				cmd   = NewTraceCmd[any](cmock.NewCmd[any]())
+				codec = NewServerCodec[any](serverCodecFn[any]{
+					decode: func(r tspt.Reader) (core.Cmd[any], int, error) {
+						return cmd, 5, nil
+					},
+				}, WithCodecMeterProvider(noop.NewMeterProvider()),
+					WithCodecSpanEvents())
+				span = mock.NewSpan().RegisterAddEvent(
+					func(name string, options ...trace.EventOption) {
+						asserterror.Equal(t, name, "decoded")
+						config := trace.NewEventConfig(options...)
+						asserterror.EqualDeep(t, config.Attributes()[0],
+							semconv.CmdStreamCommandSizeKey.Int64(5))
+					},
+				)
+			)
+			decoded, _, _ := codec.Decode(nil)
+			addDecodedEvent(decoded, span)
+			addDecodedEvent(decoded, span)
+			asserterror.EqualDeep(t, mok.CheckCalls([]*mok.Mock{span.Mock}),
+				mok.EmptyInfomap)
+		})
+}
+
+func codecMeterProvider(duration mock.Float64Histogram,
+	size mock.Int64Histogram, errors mock.Int64Counter) mock.MeterProvider {
+	meter := mock.NewMeter().RegisterFloat64Histogram(
+		func(name string, options ...metric.Float64HistogramOption) (
+			metric.Float64Histogram, error) {
+			return duration, nil
+		},
+	).RegisterInt64Histogram(
+		func(name string, options ...metric.Int64HistogramOption) (
+			metric.Int64Histogram, error) {
+			return size, nil
+		},
+	).RegisterInt64Counter(
+		func(name string, options ...metric.Int64CounterOption) (
+			metric.Int64Counter, error) {
+			return errors, nil
+		},
+	)
+	return mock.NewMeterProvider().RegisterMeter(
+		func(name string, options ...metric.MeterOption) metric.Meter {
+			return meter
+		},
+	)
+}
+
+type clientCodecFn[T any] struct {
+	encode func(cmd core.Cmd[T], w tspt.Writer) (int, error)
+	decode func(r tspt.Reader) (core.Result, int, error)
+}
+
+func (c clientCodecFn[T]) Encode(cmd core.Cmd[T], w tspt.Writer) (int, error) {
+	return c.encode(cmd, w)
+}
+
+func (c clientCodecFn[T]) Decode(r tspt.Reader) (core.Result, int, error) {
+	return c.decode(r)
+}
+
+type serverCodecFn[T any] struct {
+	encode func(result core.Result, w tspt.Writer) (int, error)
+	decode func(r tspt.Reader) (core.Cmd[T], int, error)
+}
+
+func (c serverCodecFn[T]) Encode(result core.Result, w tspt.Writer) (int, error) {
+	return c.encode(result, w)
+}
+
+func (c serverCodecFn[T]) Decode(r tspt.Reader) (core.Cmd[T], int, error) {
+	return c.decode(r)
+}
@@ -0,0 +1,48 @@
+package otelcmd
+
+import (
+	"net"
+	"sync/atomic"
+
+	"github.com/cmd-stream/cmd-stream-go/client"
+)
+
+// NewConnFactory creates a new ConnFactory.
+func NewConnFactory(factory client.ConnFactory) *ConnFactory {
+	return &ConnFactory{factory: factory}
+}
+
+// ConnFactory wraps a client.ConnFactory and remembers the remote address of
+// the last established connection. For a single client its RemoteAddr method
+// can be used with the WithRemoteAddrFn option, so that client spans report
+// the actual peer address, for example, after a reconnect to another host
+// behind DNS. The address is read when the Command is sent.
+//
+// A ConnFactory shared by several clients reports only the last dialed
+// address, for a group use NewGroup with DispatchStrategyFactory instead.
+type ConnFactory struct {
+	factory client.ConnFactory
+	addr    atomic.Pointer[connAddr]
+}
+
+type connAddr struct {
+	net.Addr
+}
+
+func (f *ConnFactory) New() (conn net.Conn, err error) {
+	conn, err = f.factory.New()
+	if err != nil {
+		return
+	}
+	f.addr.Store(&connAddr{conn.RemoteAddr()})
+	return
+}
+
+// RemoteAddr returns the remote address of the last established connection,
+// or nil if there were none.
+func (f *ConnFactory) RemoteAddr() net.Addr {
+	if addr := f.addr.Load(); addr != nil {
+		return addr.Addr
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package otelcmd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/cmd-stream/cmd-stream-go/client"
+	"github.com/cmd-stream/cmd-stream-go/sender/hooks"
+	cmock "github.com/cmd-stream/cmd-stream-go/test/mock"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	asserterror "github.com/ymz-ncnk/assert/error"
+	"github.com/ymz-ncnk/mok"
+	"go.opentelemetry.io/otel/metric/noop"
+	otel_semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+	trace_noop "go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestConnFactory(t *testing.T) {
+	var (
+		addr1 = &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 9000}
+		addr2 = &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 9000}
+	)
+
+	t.Run("RemoteAddr should return the address of the last connection",
+		func(t *testing.T) {
+			var (
+				conn1 = cmock.NewConn().RegisterRemoteAddr(
+					func() net.Addr { return addr1 },
+				)
+				conn2 = cmock.NewConn().RegisterRemoteAddr(
+					func() net.Addr { return addr2 },
+				)
+				conns   = []net.Conn{conn1, conn2}
+				factory = NewConnFactory(client.ConnFactoryFn(
+					func() (conn net.Conn, err error) {
+						if len(conns) == 0 {
+							return nil, errors.New("dial failed")
+						}
+						conn, conns = conns[0], conns[1:]
+						return
+					},
+				))
+			)
+			asserterror.Equal[net.Addr](t, factory.RemoteAddr(), nil)
+			factory.New()
+			asserterror.Equal[net.Addr](t, factory.RemoteAddr(), addr1)
+			factory.New()
+			asserterror.Equal[net.Addr](t, factory.RemoteAddr(), addr2)
+			factory.New()
+			asserterror.Equal[net.Addr](t, factory.RemoteAddr(), addr2)
+			asserterror.EqualDeep(t, mok.CheckCalls([]*mok.Mock{conn1.Mock,
+				conn2.Mock}), mok.EmptyInfomap)
+		})
+
+	t.Run("Hooks should prefer RemoteAddrFn over ServerAddr", func(t *testing.T) {
+		var (
+			factory = NewHooksFactory(
+				WithServerAddr[any](addr1),
+				WithRemoteAddrFn[any](func() net.Addr { return addr2 }),
+				WithMeterProvider[any](noop.NewMeterProvider()),
+				WithSemconvStability[any](semconv.StabilityNew),
+			)
+			h = factory.New().(*Hooks[any])
+		)
+		_, err := h.BeforeSend(context.Background(), cmock.NewCmd[any]())
+		asserterror.EqualError(t, err, nil)
+		attrs := h.semconv.SpanAttrs(h.remoteAddr, hooks.SentCmd[any]{}, nil)
+		asserterror.Equal(t, attrs[0], otel_semconv.ServerAddress("10.0.0.2"))
+	})
+
+	t.Run("Hooks should keep the address read when the Command is sent",
+		func(t *testing.T) {
+			var (
+				addr    net.Addr = addr1
+				factory          = NewHooksFactory(
+					WithRemoteAddrFn[any](func() net.Addr { return addr }),
+					WithTracerProvider[any](trace_noop.NewTracerProvider()),
+					WithMeterProvider[any](noop.NewMeterProvider()),
+				)
+				h = factory.New().(*Hooks[any])
+			)
+			_, err := h.BeforeSend(context.Background(), cmock.NewCmd[any]())
+			asserterror.EqualError(t, err, nil)
+			addr = addr2
+			asserterror.Equal[net.Addr](t, h.remoteAddr, addr1)
+		})
+}
@@ -0,0 +1,83 @@
+package otelcmd
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/cmd-stream/cmd-stream-go/core"
+)
+
+// Carrier keys used to propagate the client's time budget to the server.
+const (
+	deadlineBudgetKey = "cmd-stream-deadline-budget"
+	deadlineSentKey   = "cmd-stream-deadline-sent"
+)
+
+// injectDeadline writes the remaining time budget of the context, together
+// with the send time, into the carrier.
+func injectDeadline(ctx context.Context, carrier map[string]string,
+	now time.Time) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return
+	}
+	carrier[deadlineBudgetKey] = strconv.FormatInt(int64(deadline.Sub(now)), 10)
+	carrier[deadlineSentKey] = strconv.FormatInt(now.UnixNano(), 10)
+}
+
+// extractDeadline computes the server-side deadline from the carrier.
+//
+// The budget starts counting at receivedAt. If maxTransit is positive, it is
+// reduced by the transit time, which is measured as the difference between
+// receivedAt and the send time reported by the client, and capped at
+// maxTransit, so clock skew can't eat the whole budget. A negative transit
+// time is treated as zero.
+func extractDeadline(carrier map[string]string, receivedAt time.Time,
+	maxTransit time.Duration) (deadline time.Time, ok bool) {
+	budget, err := strconv.ParseInt(carrier[deadlineBudgetKey], 10, 64)
+	if err != nil {
+		return
+	}
+	var transit time.Duration
+	if maxTransit > 0 {
+		sent, err := strconv.ParseInt(carrier[deadlineSentKey], 10, 64)
+		if err == nil {
+			transit = min(max(receivedAt.Sub(time.Unix(0, sent)), 0), maxTransit)
+		}
+	}
+	return receivedAt.Add(time.Duration(budget) - transit), true
+}
+
+// receivedAt returns the time the server received the Command, if known,
+// otherwise the start time of the invocation.
+func receivedAt(at, startTime time.Time) time.Time {
+	if at.IsZero() {
+		return startTime
+	}
+	return at
+}
+
+// withPropagatedDeadline returns a child context with the deadline propagated
+// through the Command carrier. expired is true if the deadline has already
+// passed, the context is done then.
+func (i Invoker[T]) withPropagatedDeadline(ctx context.Context, cmd core.Cmd[T],
+	at, startTime time.Time,
+) (actx context.Context, cancel context.CancelFunc, expired bool) {
+	actx, cancel = ctx, func() {}
+	if !i.options.DeadlinePropagation {
+		return
+	}
+	tcmd, ok := cmd.(traceCmd[T])
+	if !ok {
+		return
+	}
+	deadline, ok := extractDeadline(tcmd.Carrier(), receivedAt(at, startTime),
+		i.options.DeadlineMaxTransit)
+	if !ok {
+		return
+	}
+	expired = !deadline.After(time.Now())
+	actx, cancel = context.WithDeadline(ctx, deadline)
+	return
+}
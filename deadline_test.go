@@ -0,0 +1,202 @@
+package otelcmd
+
+import (
+	"context"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cmd-stream/cmd-stream-go/core"
+	"github.com/cmd-stream/cmd-stream-go/handler"
+	cmock "github.com/cmd-stream/cmd-stream-go/test/mock"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	asserterror "github.com/ymz-ncnk/assert/error"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	trace_noop "go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestDeadline(t *testing.T) {
+	t.Run("Should not inject anything if the context has no deadline",
+		func(t *testing.T) {
+			carrier := map[string]string{}
+			injectDeadline(context.Background(), carrier, time.Now())
+			asserterror.Equal(t, len(carrier), 0)
+		})
+
+	t.Run("Budget should start counting at the receive time",
+		func(t *testing.T) {
+			var (
+				now         = time.Now()
+				ctx, cancel = context.WithDeadline(context.Background(),
+					now.Add(time.Second))
+				carrier    = map[string]string{}
+				receivedAt = now.Add(100 * time.Millisecond)
+			)
+			defer cancel()
+			injectDeadline(ctx, carrier, now)
+
+			deadline, ok := extractDeadline(carrier, receivedAt, 0)
+			asserterror.Equal(t, ok, true)
+			asserterror.Equal(t, deadline.Equal(receivedAt.Add(time.Second)), true)
+		})
+
+	t.Run("With max transit, the deadline should be adjusted for the transit time",
+		func(t *testing.T) {
+			var (
+				now         = time.Now()
+				ctx, cancel = context.WithDeadline(context.Background(),
+					now.Add(time.Second))
+				carrier    = map[string]string{}
+				receivedAt = now.Add(100 * time.Millisecond)
+			)
+			defer cancel()
+			injectDeadline(ctx, carrier, now)
+
+			deadline, ok := extractDeadline(carrier, receivedAt, time.Second)
+			asserterror.Equal(t, ok, true)
+			asserterror.Equal(t, deadline.Equal(now.Add(time.Second)), true)
+		})
+
+	t.Run("Transit time should be capped at max transit", func(t *testing.T) {
+		var (
+			now         = time.Now()
+			ctx, cancel = context.WithDeadline(context.Background(),
+				now.Add(time.Second))
+			carrier    = map[string]string{}
+			receivedAt = now.Add(time.Hour)
+			maxTransit = 100 * time.Millisecond
+		)
+		defer cancel()
+		injectDeadline(ctx, carrier, now)
+
+		deadline, ok := extractDeadline(carrier, receivedAt, maxTransit)
+		asserterror.Equal(t, ok, true)
+		asserterror.Equal(t,
+			deadline.Equal(receivedAt.Add(time.Second-maxTransit)), true)
+	})
+
+	t.Run("Negative transit time should be treated as zero", func(t *testing.T) {
+		var (
+			now         = time.Now()
+			ctx, cancel = context.WithDeadline(context.Background(),
+				now.Add(time.Second))
+			carrier    = map[string]string{}
+			receivedAt = now.Add(-time.Second)
+		)
+		defer cancel()
+		injectDeadline(ctx, carrier, now)
+
+		deadline, ok := extractDeadline(carrier, receivedAt, time.Second)
+		asserterror.Equal(t, ok, true)
+		asserterror.Equal(t, deadline.Equal(now), true)
+	})
+
+	t.Run("Invoker should detect an expired Command", func(t *testing.T) {
+		var (
+			now      = time.Now()
+			traceCmd = NewTraceCmd(cmock.NewCmd[any]())
+			invoker  = Invoker[any]{
+				options: Options[any]{DeadlinePropagation: true},
+			}
+		)
+		traceCmd.SetCarrier(map[string]string{
+			deadlineBudgetKey: "1000",
+			deadlineSentKey:   "0",
+		})
+		ctx, cancel, expired := invoker.withPropagatedDeadline(
+			context.Background(), traceCmd, now.Add(-time.Second), now)
+		defer cancel()
+		asserterror.Equal(t, expired, true)
+		asserterror.Equal(t, ctx.Err(), context.DeadlineExceeded)
+	})
+
+	t.Run("Invoker should run an expired Command unless WithSkipExpired is set",
+		func(t *testing.T) {
+			for _, skip := range []bool{false, true} {
+				var (
+					traceCmd = NewTraceCmd(cmock.NewCmd[any]())
+					executed = false
+					span     = &attrsSpan{}
+					ops      = []SetOption[any]{
+						WithDeadlinePropagation[any](),
+						WithTracerProvider[any](spanTracerProvider{span: span}),
+						WithMeterProvider[any](noop.NewMeterProvider()),
+					}
+				)
+				if skip {
+					ops = append(ops, WithSkipExpired[any]())
+				}
+				invoker := NewInvoker[any](handler.InvokerFn[any](
+					func(ctx context.Context, seq core.Seq, at time.Time,
+						bytesRead int, cmd core.Cmd[any], proxy core.Proxy) error {
+						executed = true
+						return ctx.Err()
+					}), ops...)
+				traceCmd.SetCarrier(map[string]string{deadlineBudgetKey: "1000"})
+				invoker.Invoke(context.Background(), 1, time.Now().Add(-time.Second),
+					0, traceCmd, cmock.NewProxy().RegisterRemoteAddr(
+						func() (addr net.Addr) { return nil },
+					))
+				asserterror.Equal(t, executed, !skip)
+				asserterror.Equal(t, slices.Contains(span.attrs,
+					semconv.CmdStreamCommandStatusKey.String(string(semconv.Expired))),
+					true)
+			}
+		})
+
+	t.Run("Invoker should derive a context with the propagated deadline",
+		func(t *testing.T) {
+			var (
+				now      = time.Now()
+				traceCmd = NewTraceCmd(cmock.NewCmd[any]())
+				invoker  = Invoker[any]{
+					options: Options[any]{DeadlinePropagation: true},
+				}
+			)
+			traceCmd.SetCarrier(map[string]string{
+				deadlineBudgetKey: "60000000000",
+			})
+			ctx, cancel, expired := invoker.withPropagatedDeadline(
+				context.Background(), traceCmd, time.Time{}, now)
+			defer cancel()
+			asserterror.Equal(t, expired, false)
+
+			deadline, ok := ctx.Deadline()
+			asserterror.Equal(t, ok, true)
+			asserterror.Equal(t, deadline.Equal(now.Add(time.Minute)), true)
+		})
+}
+
+// spanTracerProvider returns a tracer that starts the same span.
+type spanTracerProvider struct {
+	trace_noop.TracerProvider
+	span trace.Span
+}
+
+func (p spanTracerProvider) Tracer(name string,
+	options ...trace.TracerOption) trace.Tracer {
+	return spanTracer{span: p.span}
+}
+
+type spanTracer struct {
+	trace_noop.Tracer
+	span trace.Span
+}
+
+func (t spanTracer) Start(ctx context.Context, spanName string,
+	opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return trace.ContextWithSpan(ctx, t.span), t.span
+}
+
+// attrsSpan records the attributes set on it.
+type attrsSpan struct {
+	trace_noop.Span
+	attrs []attribute.KeyValue
+}
+
+func (s *attrsSpan) SetAttributes(kv ...attribute.KeyValue) {
+	s.attrs = append(s.attrs, kv...)
+}
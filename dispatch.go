@@ -0,0 +1,82 @@
+package otelcmd
+
+import (
+	"net"
+	"time"
+
+	"github.com/cmd-stream/cmd-stream-go/core"
+	"github.com/cmd-stream/cmd-stream-go/group"
+)
+
+// NewDispatchStrategyFactory creates a new DispatchStrategyFactory.
+func NewDispatchStrategyFactory[T any](
+	factory group.DispatchStrategyFactory[T]) DispatchStrategyFactory[T] {
+	return DispatchStrategyFactory[T]{factory}
+}
+
+// DispatchStrategyFactory wraps a group.DispatchStrategyFactory so that Hooks
+// learn the index of the client each Command is sent through and the remote
+// address of its connection at the time of sending. Hooks are created per
+// send, before the client is chosen, so this works only for TraceCmd Commands
+// created by NewTraceCmd. The index is reported if the WithClientIndex option
+// is set, the address - if the client has the RemoteAddr method, like the
+// clients of NewGroup.
+//
+// Use it with the group.WithFactory option.
+type DispatchStrategyFactory[T any] struct {
+	factory group.DispatchStrategyFactory[T]
+}
+
+func (f DispatchStrategyFactory[T]) New(
+	clients []group.Client[T],
+) group.DispatchStrategy[group.Client[T]] {
+	wrapped := make([]group.Client[T], len(clients))
+	for i := range clients {
+		wrapped[i] = indexedClient[T]{clients[i], int64(i)}
+	}
+	return f.factory.New(wrapped)
+}
+
+type indexedClient[T any] struct {
+	group.Client[T]
+	index int64
+}
+
+func (c indexedClient[T]) Send(cmd core.Cmd[T],
+	results chan<- core.AsyncResult) (seq core.Seq, n int, err error) {
+	notifyClient(cmd, c.index, c.remoteAddr())
+	return c.Client.Send(cmd, results)
+}
+
+func (c indexedClient[T]) SendWithDeadline(deadline time.Time, cmd core.Cmd[T],
+	results chan<- core.AsyncResult) (seq core.Seq, n int, err error) {
+	notifyClient(cmd, c.index, c.remoteAddr())
+	return c.Client.SendWithDeadline(deadline, cmd, results)
+}
+
+// remoteAddr returns the current remote address of the client connection, or
+// nil if the client doesn't report it.
+func (c indexedClient[T]) remoteAddr() net.Addr {
+	if a, ok := c.Client.(remoteAddrClient); ok {
+		return a.RemoteAddr()
+	}
+	return nil
+}
+
+// remoteAddrClient is a group client that reports the remote address of its
+// connection, like the clients created by NewGroup.
+type remoteAddrClient interface {
+	RemoteAddr() net.Addr
+}
+
+// notifyClient tells the Hooks of the TraceCmd the index of the client the
+// Command is sent through and the remote address of its connection.
+func notifyClient[T any](cmd core.Cmd[T], index int64, addr net.Addr) {
+	state := cmdState(cmd)
+	if state == nil {
+		return
+	}
+	if h := state.hooks.Load(); h != nil {
+		h.clientChosen(state, index, addr)
+	}
+}
@@ -0,0 +1,75 @@
+package otelcmd
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/cmd-stream/cmd-stream-go/core"
+	"github.com/cmd-stream/cmd-stream-go/group"
+	"github.com/cmd-stream/cmd-stream-go/sender/hooks"
+	cmock "github.com/cmd-stream/cmd-stream-go/test/mock"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	asserterror "github.com/ymz-ncnk/assert/error"
+	"github.com/ymz-ncnk/mok"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/noop"
+	trace_noop "go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestDispatchStrategyFactory(t *testing.T) {
+	var (
+		send = func(cmd core.Cmd[any], results chan<- core.AsyncResult) (
+			seq core.Seq, n int, err error) {
+			return
+		}
+		addrs = []net.Addr{
+			&net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 9000},
+			&net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 9000},
+		}
+		client1  = cmock.NewClient[any]().RegisterSend(send)
+		client2  = cmock.NewClient[any]().RegisterSend(send)
+		strategy = NewDispatchStrategyFactory[any](
+			group.RoundRobinStrategyFactory[any]{},
+		).New([]group.Client[any]{
+			addrGroupClient{client1, addrs[0]},
+			addrGroupClient{client2, addrs[1]},
+		})
+		factory = NewHooksFactory(
+			WithClientIndex[any](),
+			WithTracerProvider[any](trace_noop.NewTracerProvider()),
+			WithMeterProvider[any](noop.NewMeterProvider()),
+		)
+	)
+	for i := range 2 {
+		var (
+			cmd       = NewTraceCmd[any](cmock.NewCmd[any]())
+			h         = factory.New().(*Hooks[any])
+			client, _ = strategy.Next()
+		)
+		ctx, err := h.BeforeSend(context.Background(), cmd)
+		asserterror.EqualError(t, err, nil)
+		client.Send(cmd, nil)
+		asserterror.EqualDeep(t, h.clientIndexAttrs(), []attribute.KeyValue{
+			semconv.CmdStreamClientIndexKey.Int64(int64(i)),
+		})
+		asserterror.Equal(t, h.remoteAddr, addrs[i])
+
+		// The terminal call should detach Hooks from the Command.
+		h.OnResult(ctx, hooks.SentCmd[any]{Cmd: cmd},
+			hooks.ReceivedResult{Result: testResult{last: true}}, nil)
+		asserterror.Equal(t, cmd.state().hooks.Load(), nil)
+	}
+	asserterror.EqualDeep(t, mok.CheckCalls([]*mok.Mock{client1.Mock,
+		client2.Mock}), mok.EmptyInfomap)
+}
+
+// addrGroupClient is a group client that reports the remote address.
+type addrGroupClient struct {
+	cmock.Client[any]
+	addr net.Addr
+}
+
+func (c addrGroupClient) RemoteAddr() net.Addr {
+	return c.addr
+}
@@ -0,0 +1,130 @@
+package otelcmd
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"runtime/debug"
+
+	otel_semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrSpanAbandoned is the error of the client span ended after the maximum
+// span lifetime, see WithMaxSpanLifetime.
+var ErrSpanAbandoned = errors.New("span lifetime exceeded")
+
+// RecordErrorFn reports whether the error should be recorded as an exception
+// event, with a stack trace, on the span.
+type RecordErrorFn func(err error) bool
+
+// RecordAllErrors is a RecordErrorFn that records every error.
+func RecordAllErrors(err error) bool {
+	return true
+}
+
+// RecordErrorsExcept returns a RecordErrorFn that records all errors except
+// those matching, according to errors.Is, one of the specified errors. It can
+// be used to skip expected errors like timeouts.
+func RecordErrorsExcept(errs ...error) RecordErrorFn {
+	return func(err error) bool {
+		for i := range errs {
+			if errors.Is(err, errs[i]) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// ErrorDescriptionFn returns the error description that is used for the span
+// status and exception events instead of the raw error message.
+type ErrorDescriptionFn func(err error) string
+
+// FullErrorDescription is an ErrorDescriptionFn that returns the full error
+// message.
+func FullErrorDescription(err error) string {
+	return err.Error()
+}
+
+// TypeErrorDescription is an ErrorDescriptionFn that returns only the error
+// type, so no part of the message gets into the trace.
+func TypeErrorDescription(err error) string {
+	return errorTypeStr(err)
+}
+
+// ScrubErrorDescription returns an ErrorDescriptionFn that replaces all
+// matches of re in the error message with repl.
+func ScrubErrorDescription(re *regexp.Regexp, repl string) ErrorDescriptionFn {
+	return func(err error) string {
+		return re.ReplaceAllString(err.Error(), repl)
+	}
+}
+
+// errorDescription returns the description of the error according to
+// ErrorDescriptionFn.
+func (o Options[T]) errorDescription(err error) string {
+	return o.errorOptions().errorDescription(err)
+}
+
+// recordError records the error as an exception event if RecordErrorFn allows
+// it.
+func (o Options[T]) recordError(span trace.Span, err error) {
+	o.errorOptions().recordError(span, err)
+}
+
+// recordException adds an exception event to the span.
+func (o Options[T]) recordException(span trace.Span, err error, stack []byte) {
+	o.errorOptions().recordException(span, err, stack)
+}
+
+func (o Options[T]) errorOptions() errorOptions {
+	return errorOptions{o.RecordErrorFn, o.ErrorDescriptionFn}
+}
+
+// errorOptions holds the error handling functions shared by Options and
+// ClientOptions.
+type errorOptions struct {
+	recordErrorFn      RecordErrorFn
+	errorDescriptionFn ErrorDescriptionFn
+}
+
+// errorDescription returns the description of the error according to
+// errorDescriptionFn.
+func (o errorOptions) errorDescription(err error) string {
+	if o.errorDescriptionFn == nil {
+		return err.Error()
+	}
+	return o.errorDescriptionFn(err)
+}
+
+// recordError records the error as an exception event if recordErrorFn
+// allows it.
+func (o errorOptions) recordError(span trace.Span, err error) {
+	if o.recordErrorFn != nil && o.recordErrorFn(err) {
+		o.recordException(span, err, nil)
+	}
+}
+
+// recordException adds an exception event to the span. If errorDescriptionFn
+// is set, the event is built manually, because span.RecordError always uses
+// the raw error message.
+func (o errorOptions) recordException(span trace.Span, err error,
+	stack []byte) {
+	if o.errorDescriptionFn == nil {
+		span.RecordError(err, trace.WithStackTrace(true))
+		return
+	}
+	if stack == nil {
+		stack = debug.Stack()
+	}
+	span.AddEvent(otel_semconv.ExceptionEventName, trace.WithAttributes(
+		otel_semconv.ExceptionType(errorTypeStr(err)),
+		otel_semconv.ExceptionMessage(o.errorDescriptionFn(err)),
+		otel_semconv.ExceptionStacktrace(string(stack)),
+	))
+}
+
+func errorTypeStr(err error) string {
+	return fmt.Sprintf("%T", err)
+}
@@ -0,0 +1,99 @@
+package otelcmd
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/cmd-stream/otelcmd-stream-go/test/mock"
+	asserterror "github.com/ymz-ncnk/assert/error"
+	"github.com/ymz-ncnk/mok"
+	"go.opentelemetry.io/otel/attribute"
+	otel_semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRecordError(t *testing.T) {
+	t.Run("Error should be recorded with a stack trace", func(t *testing.T) {
+		var (
+			wantErr = errors.New("send failed")
+			span    = mock.NewSpan().RegisterRecordError(
+				func(err error, options ...trace.EventOption) {
+					config := trace.NewEventConfig(options...)
+					asserterror.Equal(t, err, wantErr)
+					asserterror.Equal(t, config.StackTrace(), true)
+				},
+			)
+			o = Options[any]{RecordErrorFn: RecordAllErrors}
+		)
+		o.recordError(span, wantErr)
+		asserterror.EqualDeep(t, mok.CheckCalls([]*mok.Mock{span.Mock}),
+			mok.EmptyInfomap)
+	})
+
+	t.Run("Nil RecordErrorFn should not record anything", func(t *testing.T) {
+		span := mock.NewSpan()
+		Options[any]{}.recordError(span, errors.New("send failed"))
+		asserterror.EqualDeep(t, mok.CheckCalls([]*mok.Mock{span.Mock}),
+			mok.EmptyInfomap)
+	})
+
+	t.Run("RecordErrorsExcept should skip the specified errors",
+		func(t *testing.T) {
+			var (
+				span = mock.NewSpan()
+				o    = Options[any]{
+					RecordErrorFn: RecordErrorsExcept(context.DeadlineExceeded),
+				}
+				err = errors.Join(errors.New("timeout"), context.DeadlineExceeded)
+			)
+			o.recordError(span, err)
+			asserterror.EqualDeep(t, mok.CheckCalls([]*mok.Mock{span.Mock}),
+				mok.EmptyInfomap)
+			asserterror.Equal(t, RecordErrorsExcept(context.Canceled)(err), true)
+		})
+}
+
+func TestErrorDescription(t *testing.T) {
+	var (
+		err = errors.New("user 42 not found")
+		re  = regexp.MustCompile(`[0-9]+`)
+	)
+
+	t.Run("Presets should produce expected descriptions", func(t *testing.T) {
+		asserterror.Equal(t, Options[any]{}.errorDescription(err),
+			"user 42 not found")
+		asserterror.Equal(t, FullErrorDescription(err), "user 42 not found")
+		asserterror.Equal(t, TypeErrorDescription(err), "*errors.errorString")
+		asserterror.Equal(t, ScrubErrorDescription(re, "***")(err),
+			"user *** not found")
+	})
+
+	t.Run("Exception event should contain the description", func(t *testing.T) {
+		var (
+			span = mock.NewSpan().RegisterAddEvent(
+				func(name string, options ...trace.EventOption) {
+					var (
+						config = trace.NewEventConfig(options...)
+						set    = attribute.NewSet(config.Attributes()...)
+					)
+					asserterror.Equal(t, name, otel_semconv.ExceptionEventName)
+					msg, _ := set.Value(otel_semconv.ExceptionMessageKey)
+					asserterror.Equal(t, msg.AsString(), "user *** not found")
+					typ, _ := set.Value(otel_semconv.ExceptionTypeKey)
+					asserterror.Equal(t, typ.AsString(), "*errors.errorString")
+					asserterror.Equal(t, set.HasValue(otel_semconv.ExceptionStacktraceKey),
+						true)
+				},
+			)
+			o = Options[any]{
+				RecordErrorFn:      RecordAllErrors,
+				ErrorDescriptionFn: ScrubErrorDescription(re, "***"),
+			}
+		)
+		o.recordError(span, err)
+		asserterror.EqualDeep(t, mok.CheckCalls([]*mok.Mock{span.Mock}),
+			mok.EmptyInfomap)
+	})
+}
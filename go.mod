@@ -22,3 +22,8 @@ require (
 	github.com/ymz-ncnk/mok v0.2.2
 	go.opentelemetry.io/otel v1.35.0
 )
+
+require (
+	github.com/ymz-ncnk/jointwork-go v0.0.0-20240428103805-1ee224bde88a // indirect
+	github.com/ymz-ncnk/multierr-go v0.0.0-20230813140901-5e9302c2e02a // indirect
+)
@@ -2,6 +2,8 @@ package otelcmd
 
 import (
 	"context"
+	"net"
+	"sync"
 	"time"
 
 	"github.com/cmd-stream/cmd-stream-go/core"
@@ -25,36 +27,107 @@ func NewHooksFactory[T any](ops ...SetOption[T]) HooksFactory[T] {
 		// Look BeforeSend method.
 		// TracerProvider:    otel.GetTracerProvider(),
 		MeterProvider: otel.GetMeterProvider(),
+		BaggageLimits: DefaultBaggageLimits,
+
+		SemconvStability: semconv.StabilityFromEnv(),
 	}
 	Apply(ops, &o)
-	return HooksFactory[T]{o}
+	f := HooksFactory[T]{
+		semconv: internal_semconv.NewCmdStreamClient[T](o.ServerAddr, o.Meter,
+			o.cardinalityLimiter, o.SemconvStability),
+		options: o,
+	}
+	if o.RPCSemconv {
+		f.rpc = internal_semconv.NewRPCClient[T](o.Meter,
+			o.cardinalityLimiter)
+	}
+	if o.MaxSpanLifetime > 0 || len(o.MaxSpanLifetimes) > 0 {
+		f.abandoned = internal_semconv.NewCmdStreamClientAbandoned[T](o.Meter)
+	}
+	if o.HooksPool {
+		f.pool = &sync.Pool{}
+		f.pool.New = func() any { return f.newHooks() }
+	}
+	return f
 }
 
 // HooksFactory is an implementation of the hooks.HooksFactory interface from
 // the sender module. It is responsible for creating Hooks instances configured
 // with OpenTelemetry tracing and metrics options.
 type HooksFactory[T any] struct {
-	options Options[T]
+	semconv   internal_semconv.CmdStreamClient[T]
+	rpc       internal_semconv.RPC[T]
+	abandoned internal_semconv.CmdStreamClientAbandoned[T]
+	pool      *sync.Pool
+	options   Options[T]
 }
 
 func (f HooksFactory[T]) New() hooks.Hooks[T] {
+	if f.pool != nil {
+		return f.pool.Get().(*Hooks[T])
+	}
+	return f.newHooks()
+}
+
+func (f HooksFactory[T]) newHooks() *Hooks[T] {
 	return &Hooks[T]{
-		semconv: internal_semconv.NewCmdStreamClient[T](f.options.ServerAddr, f.options.Meter),
-		options: f.options,
+		semconv:   f.semconv,
+		rpc:       f.rpc,
+		abandoned: f.abandoned,
+		pool:      f.pool,
+		options:   f.options,
 	}
 }
 
 // Hooks is an implementation of the hooks.Hooks interface from the sender
 // module. It provides OpenTelemetry-based instrumentation for the cmd-stream
 // sender.
+//
+// Hooks methods may be called from different goroutines, for example, OnTimeout
+// may fire while results are still arriving. Hooks is a state machine guarded
+// by a mutex: only the first terminal call (OnError, OnTimeout or OnResult with
+// an error or the last Result) records the Command and ends the span, all
+// calls after it are ignored.
+//
+// With the WithHooksPool option, Hooks is returned to the pool by the terminal
+// call that records the Command. The context returned by BeforeSend carries
+// the Hooks generation, calls with a context of a previous generation are
+// dropped, so late calls don't affect the next Command.
 type Hooks[T any] struct {
+	mu    sync.Mutex
+	state hooksState
+	// gen is incremented on each reuse of the pooled Hooks.
+	gen       uint64
+	pool      *sync.Pool
 	startTime time.Time
 	span      trace.Span
 	semconv   internal_semconv.CmdStreamClient[T]
+	rpc       internal_semconv.RPC[T]
+	abandoned internal_semconv.CmdStreamClientAbandoned[T]
 	options   Options[T]
+
+	// abandonTimer ends the span after the maximum span lifetime.
+	abandonTimer *time.Timer
+
+	// clientIndex holds the client index + 1, 0 means it is unknown.
+	clientIndex int64
+	// remoteAddr is the remote address of the connection the Command is sent
+	// through, nil if it is unknown.
+	remoteAddr net.Addr
+	// cmdState is the state of the TraceCmd the Hooks listen to.
+	cmdState *traceCmdState[T]
+
+	// retryAttempt is the attempt number if the Command is sent by Retrier.
+	retryAttempt int
 }
 
 func (h *Hooks[T]) BeforeSend(ctx context.Context, cmd core.Cmd[T]) (context.Context, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.state != hooksCreated {
+		return ctx, nil
+	}
+	h.state = hooksStarted
 	h.startTime = time.Now()
 	tracer := h.options.Tracer
 	if tracer == nil {
@@ -64,13 +137,45 @@ func (h *Hooks[T]) BeforeSend(ctx context.Context, cmd core.Cmd[T]) (context.Con
 			tracer = newTracer(otel.GetTracerProvider())
 		}
 	}
-	var actx context.Context
-	actx, h.span = tracer.Start(ctx, h.options.SpanNameFormatter(cmd),
-		h.options.SpanStartOptions...)
+	var (
+		actx      context.Context
+		opts      = h.options.spanStartOptions(cmd)
+		retry     = retryFromContext(ctx)
+		prevSpans []trace.SpanContext
+	)
+	if retry != nil && len(retry.spanContexts) > 0 {
+		prevSpans = retry.spanContexts
+		opts = append(opts[:len(opts):len(opts)],
+			trace.WithLinks(retryLinks(prevSpans)...))
+	}
+	actx, h.span = tracer.Start(ctx, h.options.spanName(cmd), opts...)
+	if retry != nil {
+		h.retryAttempt = retry.attempt
+		retry.spanContexts = append(retry.spanContexts, h.span.SpanContext())
+	}
+	actx = h.options.injectBaggage(actx, cmd)
+	if h.options.RemoteAddrFn != nil {
+		h.remoteAddr = h.options.RemoteAddrFn()
+	}
+	h.listenClient(cmd)
+	if d := h.options.maxSpanLifetime(cmd); d > 0 {
+		gen := h.gen
+		h.abandonTimer = time.AfterFunc(d, func() { h.abandon(ctx, cmd, gen) })
+	}
+
+	if h.pool != nil {
+		actx = context.WithValue(actx, hooksGenKey{}, h.gen)
+	}
 
 	if tcmd, ok := cmd.(traceCmd[T]); ok {
 		carrier := propagation.MapCarrier{}
 		h.options.Propagator.Inject(actx, carrier)
+		if h.options.DeadlinePropagation {
+			injectDeadline(ctx, carrier, h.startTime)
+		}
+		if len(prevSpans) > 0 {
+			injectRetryLinks(carrier, prevSpans)
+		}
 		tcmd.SetCarrier(carrier)
 	}
 	return actx, nil
@@ -78,56 +183,242 @@ func (h *Hooks[T]) BeforeSend(ctx context.Context, cmd core.Cmd[T]) (context.Con
 
 func (h *Hooks[T]) OnError(ctx context.Context, sentCmd hooks.SentCmd[T],
 	err error) {
-	if errAttr := h.semconv.ErrorTypeAttr(err); errAttr.Valid() {
-		h.span.SetAttributes(errAttr)
+	h.mu.Lock()
+	if h.stale(ctx) || !h.finish() {
+		h.mu.Unlock()
+		return
 	}
-	h.setSpanAttributes(sentCmd)
-	h.span.SetStatus(codes.Error, err.Error())
-	h.span.End()
+	h.endWithError(ctx, sentCmd, err)
+	h.unlockAndRelease()
 }
 
 func (h *Hooks[T]) OnResult(ctx context.Context, sentCmd hooks.SentCmd[T],
 	recvResult hooks.ReceivedResult, err error) {
+	h.mu.Lock()
+	if h.stale(ctx) || h.state != hooksStarted {
+		h.mu.Unlock()
+		return
+	}
 	elapsedTime := ElapsedTime(h.startTime)
 
 	if err != nil {
+		h.finish()
 		h.recordCmdMetrics(ctx, sentCmd, semconv.Failed, elapsedTime)
-		h.OnError(ctx, sentCmd, err)
+		h.endWithError(ctx, sentCmd, err)
+		h.unlockAndRelease()
 		return
 	}
 
-	h.setSpanAttributes(sentCmd)
-	h.setSpanResultEventAttributes(sentCmd, recvResult)
+	h.setSpanAttributes(ctx, sentCmd)
+	h.setSpanResultEventAttributes(ctx, sentCmd, recvResult)
 
 	h.recordResultMetrics(ctx, sentCmd, recvResult, elapsedTime)
-	if recvResult.Result.LastOne() {
-		h.recordCmdMetrics(ctx, sentCmd, semconv.Ok, elapsedTime)
-		h.span.End()
+	if !recvResult.Result.LastOne() {
+		h.mu.Unlock()
+		return
 	}
+	h.finish()
+	h.recordCmdMetrics(ctx, sentCmd, semconv.Ok, elapsedTime)
+	h.span.End()
+	h.unlockAndRelease()
 }
 
 func (h *Hooks[T]) OnTimeout(ctx context.Context, sentCmd hooks.SentCmd[T],
 	err error) {
-	elapsedTime := ElapsedTime(h.startTime)
+	h.mu.Lock()
+	if h.stale(ctx) || !h.finish() {
+		h.mu.Unlock()
+		return
+	}
+	h.recordCmdMetrics(ctx, sentCmd, semconv.Timeout, ElapsedTime(h.startTime))
+	h.endWithError(ctx, sentCmd, err)
+	h.unlockAndRelease()
+}
 
-	h.recordCmdMetrics(ctx, sentCmd, semconv.Timeout, elapsedTime)
-	h.OnError(ctx, sentCmd, err)
+// reject records the Command that was not sent because of err, fn is called
+// with the span before it ends.
+func (h *Hooks[T]) reject(ctx context.Context, cmd core.Cmd[T], err error,
+	fn func(span trace.Span)) {
+	ctx, _ = h.BeforeSend(ctx, cmd)
+	h.mu.Lock()
+	if h.stale(ctx) || !h.finish() {
+		h.mu.Unlock()
+		return
+	}
+	sentCmd := hooks.SentCmd[T]{Cmd: cmd}
+	h.recordCmdMetrics(ctx, sentCmd, semconv.Rejected, ElapsedTime(h.startTime))
+	fn(h.span)
+	h.endWithError(ctx, sentCmd, err)
+	h.unlockAndRelease()
+}
+
+// stale reports whether ctx belongs to a previous Command of the pooled Hooks,
+// such calls must be dropped. Must be called with mu held.
+func (h *Hooks[T]) stale(ctx context.Context) bool {
+	if h.pool == nil {
+		return false
+	}
+	gen, ok := ctx.Value(hooksGenKey{}).(uint64)
+	return !ok || gen != h.gen
 }
 
-func (h *Hooks[T]) setSpanAttributes(sentCmd hooks.SentCmd[T]) {
-	var addAttrs []attribute.KeyValue
-	if h.options.SpanAttributesFn != nil {
-		addAttrs = h.options.SpanAttributesFn(h.options.ServerAddr, sentCmd)
+// unlockAndRelease unlocks mu and returns Hooks to the pool, if any. It must
+// be called only by the terminal call that won finish, not by abandon, the
+// sender may still use Hooks then. Reset happens under mu, so calls waiting on
+// it see the new generation and are dropped.
+func (h *Hooks[T]) unlockAndRelease() {
+	if h.pool == nil {
+		h.mu.Unlock()
+		return
 	}
-	h.span.SetAttributes(h.semconv.SpanAttrs(sentCmd, addAttrs)...)
+	h.state = hooksCreated
+	h.gen++
+	h.startTime = time.Time{}
+	h.span = nil
+	h.abandonTimer = nil
+	h.retryAttempt = 0
+	h.clientIndex = 0
+	h.remoteAddr = nil
+	h.mu.Unlock()
+	h.pool.Put(h)
 }
 
-func (h *Hooks[T]) setSpanResultEventAttributes(sentCmd hooks.SentCmd[T],
-	recvResult hooks.ReceivedResult) {
-	if h.options.SpanResultEventAttributesFn != nil {
-		h.span.AddEvent(internal_semconv.ResultEventName, trace.WithAttributes(
-			h.options.SpanResultEventAttributesFn(sentCmd, recvResult)...,
-		))
+// withSpan calls fn with the span if it is started and not yet ended.
+func (h *Hooks[T]) withSpan(ctx context.Context, fn func(span trace.Span)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.stale(ctx) && h.state == hooksStarted {
+		fn(h.span)
+	}
+}
+
+// abandon ends the span with the ABANDONED status if no terminal hook was
+// called within the maximum span lifetime.
+func (h *Hooks[T]) abandon(ctx context.Context, cmd core.Cmd[T], gen uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.gen != gen || !h.finish() {
+		return
+	}
+	sentCmd := hooks.SentCmd[T]{Cmd: cmd}
+	h.recordCmdMetrics(ctx, sentCmd, semconv.Abandoned, ElapsedTime(h.startTime))
+	h.abandoned.RecordAbandoned(ctx, cmd, h.clientIndexAttrs())
+	h.span.SetAttributes(semconv.CmdStreamCommandStatusKey.String(
+		string(semconv.Abandoned)))
+	h.endWithError(ctx, sentCmd, ErrSpanAbandoned)
+}
+
+// finish moves Hooks to the finished state, it returns false if BeforeSend
+// wasn't called or Hooks is already finished. Must be called with mu held.
+func (h *Hooks[T]) finish() bool {
+	if h.state != hooksStarted {
+		return false
+	}
+	h.state = hooksFinished
+	if h.abandonTimer != nil {
+		h.abandonTimer.Stop()
+	}
+	if h.cmdState != nil {
+		h.cmdState.hooks.CompareAndSwap(h, nil)
+		h.cmdState = nil
+	}
+	return true
+}
+
+// endWithError ends the span with the error status. Must be called with mu
+// held.
+func (h *Hooks[T]) endWithError(ctx context.Context, sentCmd hooks.SentCmd[T],
+	err error) {
+	if errAttr := h.semconv.ErrorTypeAttr(err); errAttr.Valid() {
+		h.span.SetAttributes(errAttr)
+	}
+	h.setSpanAttributes(ctx, sentCmd)
+	h.options.recordError(h.span, err)
+	h.span.SetStatus(codes.Error, h.options.errorDescription(err))
+	h.span.End()
+}
+
+func (h *Hooks[T]) setSpanAttributes(ctx context.Context,
+	sentCmd hooks.SentCmd[T]) {
+	peerAddr := h.remoteAddr
+	if peerAddr == nil {
+		peerAddr = h.options.ServerAddr
+	}
+	addAttrs := h.options.userSpanAttributes(ctx, peerAddr, sentCmd)
+	if h.options.RPCSemconv {
+		addAttrs = append(addAttrs, h.rpc.Attrs(unwrapCmd(sentCmd.Cmd))...)
+	}
+	addAttrs = append(addAttrs, h.clientIndexAttrs()...)
+	if h.retryAttempt > 0 {
+		addAttrs = append(addAttrs,
+			semconv.CmdStreamRetryAttemptKey.Int(h.retryAttempt))
+	}
+	h.span.SetAttributes(h.semconv.SpanAttrs(h.remoteAddr, sentCmd, addAttrs)...)
+}
+
+// listenClient makes the TraceCmd notify Hooks about the client it is sent
+// through. Must be called with mu held.
+func (h *Hooks[T]) listenClient(cmd core.Cmd[T]) {
+	if state := cmdState(cmd); state != nil {
+		h.cmdState = state
+		h.cmdState.hooks.Store(h)
+	}
+}
+
+// listens reports whether the Hooks are started for the Command with the
+// specified state. A late notification of a previous Command of the pooled
+// Hooks comes with another state. Must be called with mu held.
+func (h *Hooks[T]) listens(state *traceCmdState[T]) bool {
+	return h.state == hooksStarted && h.cmdState == state
+}
+
+// encoded adds the encoded event of the ClientCodec to the span.
+func (h *Hooks[T]) encoded(state *traceCmdState[T], size int,
+	elapsedTime float64) {
+	if !h.options.CodecEvents {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.listens(state) {
+		h.span.AddEvent(internal_semconv.EncodedEventName, trace.WithAttributes(
+			internal_semconv.CodecEventAttrs(size, elapsedTime)...))
+	}
+}
+
+// clientChosen is called when the Command is sent through the client with the
+// specified index, addr is the remote address of its connection, or nil if it
+// is unknown.
+func (h *Hooks[T]) clientChosen(state *traceCmdState[T], index int64,
+	addr net.Addr) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.listens(state) {
+		return
+	}
+	h.clientIndex = index + 1
+	if addr != nil {
+		h.remoteAddr = addr
+	}
+}
+
+// clientIndexAttrs returns the cmd-stream.client.index attribute if the
+// WithClientIndex option is set and the client index is known. Must be called
+// with mu held.
+func (h *Hooks[T]) clientIndexAttrs() []attribute.KeyValue {
+	if h.options.ClientIndex && h.clientIndex > 0 {
+		return []attribute.KeyValue{
+			semconv.CmdStreamClientIndexKey.Int64(h.clientIndex - 1)}
+	}
+	return nil
+}
+
+func (h *Hooks[T]) setSpanResultEventAttributes(ctx context.Context,
+	sentCmd hooks.SentCmd[T], recvResult hooks.ReceivedResult) {
+	if attrs, ok := h.options.userResultEventAttributes(ctx, sentCmd,
+		recvResult); ok {
+		h.span.AddEvent(internal_semconv.ResultEventName,
+			trace.WithAttributes(attrs...))
 	}
 }
 
@@ -136,11 +427,13 @@ func (h *Hooks[T]) recordCmdMetrics(ctx context.Context,
 	status semconv.CmdStreamCommandStatus,
 	elapsedTime float64,
 ) {
-	var addAttrs []attribute.KeyValue
-	if h.options.CmdMetricAttributesFn != nil {
-		addAttrs = h.options.CmdMetricAttributesFn(sentCmd, status, elapsedTime)
-	}
+	addAttrs := h.options.userCmdMetricAttributes(ctx, sentCmd, status,
+		elapsedTime)
+	addAttrs = append(addAttrs, h.clientIndexAttrs()...)
 	h.semconv.RecordCmdMetrics(ctx, sentCmd, status, elapsedTime, addAttrs)
+	if h.options.RPCSemconv {
+		h.rpc.RecordDuration(ctx, unwrapCmd(sentCmd.Cmd), status, elapsedTime)
+	}
 }
 
 func (h *Hooks[T]) recordResultMetrics(ctx context.Context,
@@ -148,13 +441,24 @@ func (h *Hooks[T]) recordResultMetrics(ctx context.Context,
 	recvResult hooks.ReceivedResult,
 	elapsedTime float64,
 ) {
-	var addAttrs []attribute.KeyValue
-	if h.options.ResultMetricAttributesFn != nil {
-		addAttrs = h.options.ResultMetricAttributesFn(sentCmd, recvResult, elapsedTime)
-	}
+	addAttrs := h.options.userResultMetricAttributes(ctx, sentCmd, recvResult,
+		elapsedTime)
+	addAttrs = append(addAttrs, h.clientIndexAttrs()...)
 	h.semconv.RecordResultMetrics(ctx, sentCmd, recvResult, elapsedTime, addAttrs)
 }
 
+// hooksGenKey is the context key of the pooled Hooks generation.
+type hooksGenKey struct{}
+
+// hooksState is the state of Hooks.
+type hooksState int
+
+const (
+	hooksCreated hooksState = iota
+	hooksStarted
+	hooksFinished
+)
+
 func newTracer(tp trace.TracerProvider) trace.Tracer {
 	return tp.Tracer(ScopeName, trace.WithInstrumentationVersion(Version()))
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"net"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -676,6 +678,7 @@ func testOnError(addAttrs []attribute.KeyValue, t *testing.T) {
 		),
 	).New()
 	hooks.(*Hooks[any]).span = span
+	hooks.(*Hooks[any]).state = hooksStarted
 
 	hooks.OnError(context.Background(), sentCmd, err)
 }
@@ -694,6 +697,7 @@ func testOnResult(ctxWithSpan context.Context, sentCmd hooks.SentCmd[any],
 	}
 	hooks := NewHooksFactory(ops...).New()
 	hooks.(*Hooks[any]).span = span
+	hooks.(*Hooks[any]).state = hooksStarted
 
 	hooks.OnResult(ctxWithSpan, sentCmd, recvResult, err)
 
@@ -710,6 +714,7 @@ func testOnTimeout(ctxWithSpan context.Context, sentCmd hooks.SentCmd[any],
 	mocks := []*mok.Mock{sentCmd.Cmd.(cmock.Cmd[any]).Mock, meterProvider.Mock, span.Mock}
 	hooks := NewHooksFactory[any](ops...).New()
 	hooks.(*Hooks[any]).span = span
+	hooks.(*Hooks[any]).state = hooksStarted
 
 	hooks.OnTimeout(ctxWithSpan, sentCmd, err)
 
@@ -735,6 +740,18 @@ func mockClientMeterProvider(meterProvider mock.MeterProvider, t *testing.T) (
 	return
 }
 
+// mockClientMeter returns a Meter that expects creation of the client
+// instruments.
+func mockClientMeter(t *testing.T) mock.Meter {
+	_, fn1, fn2, fn3, fn4, fn5, fn6 := clientMeterFns(t)
+	return mock.NewMeter().RegisterInt64Counter(fn1).
+		RegisterInt64Histogram(fn2).
+		RegisterFloat64Histogram(fn3).
+		RegisterInt64Counter(fn4).
+		RegisterInt64Histogram(fn5).
+		RegisterFloat64Histogram(fn6)
+}
+
 func clientMeterFns(t *testing.T) (vars metricVars, fn1 mock.Int64CounterFn,
 	fn2 mock.Int64HistogramFn,
 	fn3 mock.Float64HistogramFn,
@@ -827,3 +844,354 @@ func clientMeterFns(t *testing.T) (vars metricVars, fn1 mock.Int64CounterFn,
 	}
 	return
 }
+
+func TestHooksConcurrency(t *testing.T) {
+	newHooks := func(t *testing.T, ops ...SetOption[any]) (*Hooks[any],
+		*countingSpan) {
+		ops = append([]SetOption[any]{
+			WithTracerProvider[any](tracenop.NewTracerProvider()),
+			WithMeterProvider[any](noop.NewMeterProvider()),
+		}, ops...)
+		h := NewHooksFactory[any](ops...).New().(*Hooks[any])
+		_, err := h.BeforeSend(context.Background(), cmock.NewCmd[any]())
+		asserterror.EqualError(t, err, nil)
+		h.mu.Lock()
+		span := &countingSpan{Span: h.span}
+		h.span = span
+		h.mu.Unlock()
+		return h, span
+	}
+
+	t.Run("Concurrent OnResult and OnTimeout should end the span once",
+		func(t *testing.T) {
+			for range 100 {
+				var (
+					h, span = newHooks(t)
+					sentCmd = hooks.SentCmd[any]{Cmd: cmock.NewCmd[any]()}
+					wg      sync.WaitGroup
+				)
+				wg.Add(3)
+				go func() {
+					defer wg.Done()
+					h.OnResult(context.Background(), sentCmd,
+						hooks.ReceivedResult{Result: testResult{}}, nil)
+				}()
+				go func() {
+					defer wg.Done()
+					h.OnResult(context.Background(), sentCmd,
+						hooks.ReceivedResult{Result: testResult{last: true}}, nil)
+				}()
+				go func() {
+					defer wg.Done()
+					h.OnTimeout(context.Background(), sentCmd,
+						context.DeadlineExceeded)
+				}()
+				wg.Wait()
+				asserterror.Equal(t, span.ends.Load(), int64(1))
+			}
+		})
+
+	t.Run("Calls after the terminal one should be ignored", func(t *testing.T) {
+		var (
+			h, span = newHooks(t)
+			sentCmd = hooks.SentCmd[any]{Cmd: cmock.NewCmd[any]()}
+		)
+		h.OnTimeout(context.Background(), sentCmd, context.DeadlineExceeded)
+		h.OnResult(context.Background(), sentCmd,
+			hooks.ReceivedResult{Result: testResult{last: true}}, nil)
+		h.OnError(context.Background(), sentCmd, errors.New("error"))
+		asserterror.Equal(t, span.ends.Load(), int64(1))
+		asserterror.Equal(t, span.events.Load(), int64(0))
+	})
+}
+
+func TestHooksMaxSpanLifetime(t *testing.T) {
+	t.Run("Span should be abandoned after the max lifetime", func(t *testing.T) {
+		var (
+			h = NewHooksFactory[any](
+				WithTracerProvider[any](countingTracerProvider{}),
+				WithMeterProvider[any](noop.NewMeterProvider()),
+				WithMaxSpanLifetime[any](10*time.Millisecond),
+			).New().(*Hooks[any])
+			cmd = cmock.NewCmd[any]()
+		)
+		_, err := h.BeforeSend(context.Background(), cmd)
+		asserterror.EqualError(t, err, nil)
+		span := h.span.(*countingSpan)
+		waitFor(t, func() bool { return span.ends.Load() == 1 })
+		h.OnResult(context.Background(), hooks.SentCmd[any]{Cmd: cmd},
+			hooks.ReceivedResult{Result: testResult{last: true}}, nil)
+		asserterror.Equal(t, span.ends.Load(), int64(1))
+		asserterror.Equal(t, span.status.Load(), int64(codes.Error))
+	})
+
+	t.Run("Terminal hook should stop the abandon timer", func(t *testing.T) {
+		var (
+			h = NewHooksFactory[any](
+				WithTracerProvider[any](countingTracerProvider{}),
+				WithMeterProvider[any](noop.NewMeterProvider()),
+				WithMaxSpanLifetime[any](10*time.Millisecond),
+			).New().(*Hooks[any])
+			cmd = cmock.NewCmd[any]()
+		)
+		_, err := h.BeforeSend(context.Background(), cmd)
+		asserterror.EqualError(t, err, nil)
+		span := h.span.(*countingSpan)
+		h.OnResult(context.Background(), hooks.SentCmd[any]{Cmd: cmd},
+			hooks.ReceivedResult{Result: testResult{last: true}}, nil)
+		time.Sleep(30 * time.Millisecond)
+		asserterror.Equal(t, span.ends.Load(), int64(1))
+		asserterror.Equal(t, span.status.Load(), int64(codes.Unset))
+	})
+
+	t.Run("Command type lifetime should override the default one",
+		func(t *testing.T) {
+			var (
+				cmd = cmock.NewCmd[any]()
+				o   = Options[any]{}
+			)
+			Apply([]SetOption[any]{
+				WithMaxSpanLifetime[any](time.Second),
+				WithCmdMaxSpanLifetime[any](internal_semconv.TypeStr(cmd), 0),
+			}, &o)
+			asserterror.Equal(t, o.maxSpanLifetime(cmd), time.Duration(0))
+
+			o = Options[any]{}
+			Apply([]SetOption[any]{
+				WithMaxSpanLifetime[any](time.Second),
+				WithCmdMaxSpanLifetime[any]("OtherCmd", 0),
+			}, &o)
+			asserterror.Equal(t, o.maxSpanLifetime(cmd), time.Second)
+		})
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition is not met")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// countingTracerProvider creates countingSpans.
+type countingTracerProvider struct {
+	tracenop.TracerProvider
+}
+
+func (p countingTracerProvider) Tracer(name string,
+	options ...trace.TracerOption) trace.Tracer {
+	return countingTracer{}
+}
+
+type countingTracer struct {
+	tracenop.Tracer
+}
+
+func (t countingTracer) Start(ctx context.Context, spanName string,
+	opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	span := &countingSpan{Span: tracenop.Span{}}
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+// countingSpan counts End and AddEvent calls.
+type countingSpan struct {
+	trace.Span
+	ends   atomic.Int64
+	events atomic.Int64
+	status atomic.Int64
+}
+
+func (s *countingSpan) SetStatus(code codes.Code, description string) {
+	s.status.Store(int64(code))
+}
+
+func (s *countingSpan) End(options ...trace.SpanEndOption) {
+	s.ends.Add(1)
+}
+
+func (s *countingSpan) AddEvent(name string, options ...trace.EventOption) {
+	s.events.Add(1)
+}
+
+type testResult struct {
+	last bool
+}
+
+func (r testResult) LastOne() bool { return r.last }
+
+func TestHooksPool(t *testing.T) {
+	t.Run("Released Hooks should be reset", func(t *testing.T) {
+		var (
+			h = newBenchmarkHooksFactory(
+				WithHooksPool[any](),
+				WithMaxSpanLifetime[any](time.Hour),
+			).New().(*Hooks[any])
+			cmd     = cmock.NewCmd[any]()
+			sentCmd = hooks.SentCmd[any]{Cmd: cmd}
+		)
+		hctx, err := h.BeforeSend(context.Background(), cmd)
+		asserterror.EqualError(t, err, nil)
+		gen := h.gen
+		h.OnResult(hctx, sentCmd,
+			hooks.ReceivedResult{Result: testResult{last: true}}, nil)
+		asserterror.Equal(t, h.state, hooksCreated)
+		asserterror.Equal(t, h.gen, gen+1)
+		asserterror.Equal(t, h.span, nil)
+
+		// The abandon timer of the previous Command should not finish the
+		// next one.
+		_, err = h.BeforeSend(context.Background(), cmd)
+		asserterror.EqualError(t, err, nil)
+		h.abandon(context.Background(), cmd, gen)
+		asserterror.Equal(t, h.state, hooksStarted)
+
+		// Late calls of the previous Command should not finish the next one.
+		h.OnTimeout(hctx, sentCmd, context.DeadlineExceeded)
+		h.OnError(hctx, sentCmd, errors.New("send error"))
+		h.OnResult(hctx, sentCmd,
+			hooks.ReceivedResult{Result: testResult{last: true}}, nil)
+		asserterror.Equal(t, h.state, hooksStarted)
+		asserterror.Equal(t, h.gen, gen+1)
+	})
+
+	t.Run("Late notifications of the previous Command should be dropped",
+		func(t *testing.T) {
+			var (
+				h = newBenchmarkHooksFactory(
+					WithHooksPool[any](),
+					WithCodecEvents[any](),
+					WithTracerProvider[any](countingTracerProvider{}),
+				).New().(*Hooks[any])
+				prevCmd = NewTraceCmd(cmock.NewCmd[any]())
+				nextCmd = NewTraceCmd(cmock.NewCmd[any]())
+				addr    = &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9000}
+			)
+			hctx, err := h.BeforeSend(context.Background(), prevCmd)
+			asserterror.EqualError(t, err, nil)
+			h.OnResult(hctx, hooks.SentCmd[any]{Cmd: prevCmd},
+				hooks.ReceivedResult{Result: testResult{last: true}}, nil)
+
+			_, err = h.BeforeSend(context.Background(), nextCmd)
+			asserterror.EqualError(t, err, nil)
+			span := h.span.(*countingSpan)
+
+			h.encoded(prevCmd.state(), 10, 0.1)
+			h.clientChosen(prevCmd.state(), 5, addr)
+			asserterror.Equal(t, span.events.Load(), int64(0))
+			asserterror.Equal(t, h.clientIndex, int64(0))
+			asserterror.Equal(t, h.remoteAddr, nil)
+
+			h.encoded(nextCmd.state(), 10, 0.1)
+			h.clientChosen(nextCmd.state(), 5, addr)
+			asserterror.Equal(t, span.events.Load(), int64(1))
+			asserterror.Equal(t, h.clientIndex, int64(6))
+			asserterror.Equal[net.Addr](t, h.remoteAddr, addr)
+		})
+
+	t.Run("Concurrent terminal calls should release Hooks once",
+		func(t *testing.T) {
+			var (
+				factory = newBenchmarkHooksFactory(WithHooksPool[any]())
+				cmd     = cmock.NewCmd[any]()
+				sentCmd = hooks.SentCmd[any]{Cmd: cmd}
+				recv    = hooks.ReceivedResult{Result: testResult{last: true}}
+			)
+			for range 100 {
+				var (
+					h       = factory.New().(*Hooks[any])
+					hctx, _ = h.BeforeSend(context.Background(), cmd)
+					gen     = h.gen
+					wg      sync.WaitGroup
+				)
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+					h.OnTimeout(hctx, sentCmd, context.DeadlineExceeded)
+				}()
+				go func() {
+					defer wg.Done()
+					h.OnResult(hctx, sentCmd, recv, nil)
+				}()
+				wg.Wait()
+				h.mu.Lock()
+				asserterror.Equal(t, h.gen, gen+1)
+				h.mu.Unlock()
+			}
+		})
+
+	t.Run("Not last Result should not release Hooks", func(t *testing.T) {
+		var (
+			h   = newBenchmarkHooksFactory(WithHooksPool[any]()).New().(*Hooks[any])
+			cmd = cmock.NewCmd[any]()
+		)
+		hctx, err := h.BeforeSend(context.Background(), cmd)
+		asserterror.EqualError(t, err, nil)
+		gen := h.gen
+		h.OnResult(hctx, hooks.SentCmd[any]{Cmd: cmd},
+			hooks.ReceivedResult{Result: testResult{}}, nil)
+		asserterror.Equal(t, h.state, hooksStarted)
+		asserterror.Equal(t, h.gen, gen)
+	})
+
+	t.Run("Pooled Hooks should not exceed the allocation target",
+		func(t *testing.T) {
+			var (
+				factory = newBenchmarkHooksFactory(WithHooksPool[any]())
+				cmd     = cmock.NewCmd[any]()
+				sentCmd = hooks.SentCmd[any]{Cmd: cmd}
+				recv    = hooks.ReceivedResult{Result: testResult{last: true}}
+				ctx     = context.Background()
+			)
+			allocs := testing.AllocsPerRun(1000, func() {
+				h := factory.New()
+				hctx, _ := h.BeforeSend(ctx, cmd)
+				h.OnResult(hctx, sentCmd, recv, nil)
+			})
+			if allocs > hooksAllocsTarget {
+				t.Errorf("allocs per Command = %v, want <= %v", allocs,
+					hooksAllocsTarget)
+			}
+		})
+}
+
+// hooksAllocsTarget is the maximum number of allocations per Command for the
+// pooled Hooks with the noop providers: the span name, the span context, the
+// span attributes and the generation context.
+const hooksAllocsTarget = 4
+
+func BenchmarkHooks(b *testing.B) {
+	b.Run("New", func(b *testing.B) {
+		benchmarkHooks(b)
+	})
+	b.Run("Pool", func(b *testing.B) {
+		benchmarkHooks(b, WithHooksPool[any]())
+	})
+}
+
+func benchmarkHooks(b *testing.B, ops ...SetOption[any]) {
+	var (
+		factory = newBenchmarkHooksFactory(ops...)
+		cmd     = cmock.NewCmd[any]()
+		sentCmd = hooks.SentCmd[any]{Seq: 1, Size: 10, Cmd: cmd}
+		recv    = hooks.ReceivedResult{Seq: 1, Size: 20,
+			Result: testResult{last: true}}
+		ctx = context.Background()
+	)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for b.Loop() {
+		h := factory.New()
+		hctx, _ := h.BeforeSend(ctx, cmd)
+		h.OnResult(hctx, sentCmd, recv, nil)
+	}
+}
+
+func newBenchmarkHooksFactory(ops ...SetOption[any]) HooksFactory[any] {
+	ops = append([]SetOption[any]{
+		WithTracerProvider[any](tracenop.NewTracerProvider()),
+		WithMeterProvider[any](noop.NewMeterProvider()),
+	}, ops...)
+	return NewHooksFactory[any](ops...)
+}
@@ -0,0 +1,46 @@
+package semconv
+
+import (
+	"context"
+
+	"github.com/cmd-stream/cmd-stream-go/core"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+func NewCmdStreamClientAbandoned[T any](meter metric.Meter) (
+	a CmdStreamClientAbandoned[T]) {
+	if meter == nil {
+		a.abandonedCounter = noop.Int64Counter{}
+		return
+	}
+	var err error
+	a.abandonedCounter, err = meter.Int64Counter(
+		semconv.CmdStreamClientCommandAbandonedCountName,
+		metric.WithUnit(semconv.CmdStreamClientCommandAbandonedCountUnit),
+		metric.WithDescription(semconv.CmdStreamClientCommandAbandonedCountDescription),
+	)
+	handleErr(err)
+	return
+}
+
+type CmdStreamClientAbandoned[T any] struct {
+	abandonedCounter metric.Int64Counter
+}
+
+// RecordAbandoned records the Command whose span was ended after the maximum
+// span lifetime.
+func (a CmdStreamClientAbandoned[T]) RecordAbandoned(ctx context.Context,
+	cmd core.Cmd[T],
+	addAttrs []attribute.KeyValue,
+) {
+	var (
+		l     = len(addAttrs)
+		attrs = make([]attribute.KeyValue, l, l+1)
+	)
+	copy(attrs, addAttrs)
+	attrs = append(attrs, semconv.CmdStreamCommandTypeKey.String(TypeStr(cmd)))
+	a.abandonedCounter.Add(ctx, 1, metric.WithAttributeSet(attribute.NewSet(attrs...)))
+}
@@ -0,0 +1,80 @@
+package semconv
+
+import (
+	"net"
+
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"go.opentelemetry.io/otel/attribute"
+	otel_semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+)
+
+// ProtocolName is the network.protocol.name value of the new address
+// attributes.
+const ProtocolName = "cmd-stream"
+
+// ClientAddrAttrs returns the address attributes of a client span for the
+// specified Stability. In the StabilityMigration mode network.protocol.name
+// gets the new value, see semconv.StabilityMigration.
+func ClientAddrAttrs(addr net.Addr,
+	stability semconv.Stability) []attribute.KeyValue {
+	switch stability {
+	case semconv.StabilityNew:
+		return newClientAddrAttrs(addr)
+	case semconv.StabilityMigration:
+		attrs := AddrAttrs(addr)
+		return append(attrs[:2], newClientAddrAttrs(addr)...)
+	default:
+		return AddrAttrs(addr)
+	}
+}
+
+// ServerAddrAttrs returns the address attributes of a server span for the
+// specified Stability, addr is the address of the peer.
+func ServerAddrAttrs(addr net.Addr,
+	stability semconv.Stability) []attribute.KeyValue {
+	if stability == semconv.StabilityOld {
+		return AddrAttrs(addr)
+	}
+	if addr == nil {
+		return nil
+	}
+	attrs := AddrAttrs(addr)
+	return append(attrs[:2],
+		otel_semconv.NetworkTransportKey.String(transport(addr)),
+		otel_semconv.NetworkProtocolName(ProtocolName),
+	)
+}
+
+func newClientAddrAttrs(addr net.Addr) []attribute.KeyValue {
+	if addr == nil {
+		return nil
+	}
+	var (
+		t     = transport(addr)
+		attrs = make([]attribute.KeyValue, 0, 4)
+	)
+	if t == "unix" {
+		attrs = append(attrs, otel_semconv.ServerAddress(addr.String()))
+	} else {
+		address, port := addressPort(addr)
+		attrs = append(attrs, otel_semconv.ServerAddress(address),
+			otel_semconv.ServerPort(port))
+	}
+	return append(attrs,
+		otel_semconv.NetworkTransportKey.String(t),
+		otel_semconv.NetworkProtocolName(ProtocolName),
+	)
+}
+
+func transport(addr net.Addr) string {
+	switch network := addr.Network(); network {
+	case "tcp", "tcp4", "tcp6":
+		return "tcp"
+	case "udp", "udp4", "udp6":
+		return "udp"
+	case "unix", "unixgram", "unixpacket":
+		return "unix"
+	default:
+		return network
+	}
+}
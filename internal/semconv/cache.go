@@ -0,0 +1,87 @@
+package semconv
+
+import (
+	"net"
+	"reflect"
+	"sync"
+
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// addrAttrsCacheSize is the maximum number of cached address attributes, the
+// cache is cleared when it is reached.
+const addrAttrsCacheSize = 1024
+
+// typeNames maps reflect.Type to the type name.
+var typeNames sync.Map
+
+// typeName returns the name of the type, it is computed once per type.
+func typeName(t reflect.Type) string {
+	if name, ok := typeNames.Load(t); ok {
+		return name.(string)
+	}
+	name := t.Name()
+	typeNames.Store(t, name)
+	return name
+}
+
+type addrAttrsKey struct {
+	addr      net.Addr
+	server    bool
+	stability semconv.Stability
+}
+
+// addrAttrsCache caches address attributes per net.Addr identity, so they are
+// computed once per connection, for example, *net.TCPAddr returned by
+// net.Conn.RemoteAddr is the same for all calls.
+type addrAttrsCache struct {
+	mu    sync.RWMutex
+	attrs map[addrAttrsKey][]attribute.KeyValue
+}
+
+var addrAttrs = addrAttrsCache{
+	attrs: make(map[addrAttrsKey][]attribute.KeyValue),
+}
+
+// get returns the cached attributes or computes them with fn. The returned
+// slice must not be modified, its capacity equals its length, so append
+// copies it.
+func (c *addrAttrsCache) get(key addrAttrsKey,
+	fn func() []attribute.KeyValue) []attribute.KeyValue {
+	if key.addr == nil || !reflect.TypeOf(key.addr).Comparable() {
+		return fn()
+	}
+	c.mu.RLock()
+	attrs, ok := c.attrs[key]
+	c.mu.RUnlock()
+	if ok {
+		return attrs
+	}
+	attrs = fn()
+	attrs = attrs[:len(attrs):len(attrs)]
+	c.mu.Lock()
+	if len(c.attrs) >= addrAttrsCacheSize {
+		clear(c.attrs)
+	}
+	c.attrs[key] = attrs
+	c.mu.Unlock()
+	return attrs
+}
+
+// CachedClientAddrAttrs is a cached version of ClientAddrAttrs, the returned
+// slice must not be modified.
+func CachedClientAddrAttrs(addr net.Addr,
+	stability semconv.Stability) []attribute.KeyValue {
+	return addrAttrs.get(addrAttrsKey{addr: addr, stability: stability},
+		func() []attribute.KeyValue { return ClientAddrAttrs(addr, stability) })
+}
+
+// CachedServerAddrAttrs is a cached version of ServerAddrAttrs, the returned
+// slice must not be modified.
+func CachedServerAddrAttrs(addr net.Addr,
+	stability semconv.Stability) []attribute.KeyValue {
+	return addrAttrs.get(
+		addrAttrsKey{addr: addr, server: true, stability: stability},
+		func() []attribute.KeyValue { return ServerAddrAttrs(addr, stability) })
+}
@@ -0,0 +1,128 @@
+package semconv
+
+import (
+	"net"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	asserterror "github.com/ymz-ncnk/assert/error"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type benchCmd struct{}
+
+type benchGenericCmd[T any] struct{}
+
+func TestTypeStr(t *testing.T) {
+	for _, c := range []struct {
+		v    any
+		want string
+	}{
+		{benchCmd{}, "benchCmd"},
+		{benchGenericCmd[map[string]int]{}, "benchGenericCmd[map[string]int]"},
+	} {
+		asserterror.Equal(t, TypeStr(c.v), c.want)
+		name, ok := typeNames.Load(reflect.TypeOf(c.v))
+		asserterror.Equal(t, ok, true)
+		asserterror.Equal(t, name, any(c.want))
+		asserterror.Equal(t, TypeStr(c.v), c.want)
+	}
+}
+
+func TestCachedAddrAttrs(t *testing.T) {
+	t.Run("Cached attributes should be equal to the computed ones",
+		func(t *testing.T) {
+			addr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9000}
+			for _, stability := range []semconv.Stability{semconv.StabilityOld,
+				semconv.StabilityNew, semconv.StabilityMigration} {
+				asserterror.EqualDeep(t, CachedServerAddrAttrs(addr, stability),
+					ServerAddrAttrs(addr, stability))
+				asserterror.EqualDeep(t, CachedServerAddrAttrs(addr, stability),
+					ServerAddrAttrs(addr, stability))
+				asserterror.EqualDeep(t, CachedClientAddrAttrs(addr, stability),
+					ClientAddrAttrs(addr, stability))
+			}
+		})
+
+	t.Run("Append to the cached attributes should not modify them",
+		func(t *testing.T) {
+			var (
+				addr  = &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9001}
+				attrs = CachedServerAddrAttrs(addr, semconv.StabilityOld)
+				want  = ServerAddrAttrs(addr, semconv.StabilityOld)
+			)
+			_ = append(attrs, semconv.CmdStreamCommandSeqKey.Int(1))
+			asserterror.Equal(t, cap(attrs), len(attrs))
+			asserterror.EqualDeep(t, CachedServerAddrAttrs(addr,
+				semconv.StabilityOld), want)
+		})
+
+	t.Run("Cache should not grow beyond the limit", func(t *testing.T) {
+		for i := range addrAttrsCacheSize + 1 {
+			CachedServerAddrAttrs(&net.TCPAddr{Port: i}, semconv.StabilityOld)
+		}
+		addrAttrs.mu.RLock()
+		defer addrAttrs.mu.RUnlock()
+		if len(addrAttrs.attrs) > addrAttrsCacheSize {
+			t.Errorf("cache size = %d, want <= %d", len(addrAttrs.attrs),
+				addrAttrsCacheSize)
+		}
+	})
+}
+
+func TestMetricOptionCache(t *testing.T) {
+	t.Run("Cache should not grow beyond the limit", func(t *testing.T) {
+		var (
+			c   metricOptionCache
+			ops = newMetricOptions(attribute.NewSet())
+		)
+		for i := range metricOptionCacheSize + 1 {
+			key := metricOptionKey{strconv.Itoa(i), "OK"}
+			c.store(key, ops)
+			c.store(key, ops)
+			c.storeResult(key, ops)
+		}
+		asserterror.Equal(t, c.cmdLen.Load(), int64(metricOptionCacheSize))
+		asserterror.Equal(t, c.resultLen.Load(), int64(metricOptionCacheSize))
+		_, ok := c.load(metricOptionKey{"0", "OK"})
+		asserterror.Equal(t, ok, true)
+		_, ok = c.load(metricOptionKey{strconv.Itoa(metricOptionCacheSize), "OK"})
+		asserterror.Equal(t, ok, false)
+	})
+}
+
+func BenchmarkTypeStr(b *testing.B) {
+	for _, cmd := range []any{benchCmd{}, benchGenericCmd[map[string]int]{}} {
+		name := reflect.TypeOf(cmd).Name()
+		b.Run("Reflect "+name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				_ = reflect.TypeOf(cmd).Name()
+			}
+		})
+		b.Run("Cached "+name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				_ = TypeStr(cmd)
+			}
+		})
+	}
+}
+
+func BenchmarkServerAddrAttrs(b *testing.B) {
+	addr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9000}
+	b.Run("Computed", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = ServerAddrAttrs(addr, semconv.StabilityOld)
+		}
+	})
+	b.Run("Cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = CachedServerAddrAttrs(addr, semconv.StabilityOld)
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package semconv
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// OtherValue replaces attribute values that exceed the cardinality limit.
+const OtherValue = "_OTHER"
+
+// NewCardinalityLimiter creates a new CardinalityLimiter with the specified
+// per-key limits.
+func NewCardinalityLimiter(limits map[attribute.Key]int,
+	meter metric.Meter) *CardinalityLimiter {
+	l := &CardinalityLimiter{
+		limits: limits,
+		seen:   make(map[attribute.Key]map[attribute.Value]struct{}, len(limits)),
+	}
+	if meter == nil {
+		l.overflowCounter = noop.Int64Counter{}
+		return l
+	}
+	var err error
+	l.overflowCounter, err = meter.Int64Counter(
+		semconv.CmdStreamAttributeCardinalityOverflowCountName,
+		metric.WithUnit(semconv.CmdStreamAttributeCardinalityOverflowCountUnit),
+		metric.WithDescription(semconv.CmdStreamAttributeCardinalityOverflowCountDescription),
+	)
+	handleErr(err)
+	return l
+}
+
+// CardinalityLimiter limits the number of distinct values of metric
+// attributes. Once the limit for a key is reached, new values of this key are
+// replaced with OtherValue.
+//
+// A nil CardinalityLimiter leaves attributes untouched.
+type CardinalityLimiter struct {
+	limits          map[attribute.Key]int
+	mu              sync.RWMutex
+	seen            map[attribute.Key]map[attribute.Value]struct{}
+	overflowCounter metric.Int64Counter
+}
+
+// Limit replaces, in place, the attribute values that exceed the limits.
+func (l *CardinalityLimiter) Limit(ctx context.Context,
+	attrs []attribute.KeyValue) {
+	if l == nil {
+		return
+	}
+	for i := range attrs {
+		limit, ok := l.limits[attrs[i].Key]
+		if !ok || l.allow(attrs[i], limit) {
+			continue
+		}
+		l.overflowCounter.Add(ctx, 1, metric.WithAttributeSet(attribute.NewSet(
+			semconv.CmdStreamAttributeKeyKey.String(string(attrs[i].Key)),
+		)))
+		attrs[i] = attrs[i].Key.String(OtherValue)
+	}
+}
+
+func (l *CardinalityLimiter) allow(attr attribute.KeyValue, limit int) bool {
+	l.mu.RLock()
+	_, ok := l.seen[attr.Key][attr.Value]
+	l.mu.RUnlock()
+	if ok {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	values := l.seen[attr.Key]
+	if _, ok = values[attr.Value]; ok {
+		return true
+	}
+	if len(values) >= limit {
+		return false
+	}
+	if values == nil {
+		values = make(map[attribute.Value]struct{})
+		l.seen[attr.Key] = values
+	}
+	values[attr.Value] = struct{}{}
+	return true
+}
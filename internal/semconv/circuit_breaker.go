@@ -0,0 +1,34 @@
+package semconv
+
+import (
+	"context"
+
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// CircuitBreakerStateChangeEventName is the name of the span event added when
+// the circuit breaker changes its state.
+const CircuitBreakerStateChangeEventName = "circuit_breaker_state_change"
+
+// RegisterCircuitBreakerState registers the
+// cmd-stream.client.circuit_breaker.state gauge, which observes the state
+// returned by stateFn.
+func RegisterCircuitBreakerState(meter metric.Meter,
+	stateFn func() semconv.CmdStreamCircuitBreakerState) {
+	if meter == nil {
+		return
+	}
+	_, err := meter.Int64ObservableGauge(
+		semconv.CmdStreamClientCircuitBreakerStateName,
+		metric.WithUnit(semconv.CmdStreamClientCircuitBreakerStateUnit),
+		metric.WithDescription(semconv.CmdStreamClientCircuitBreakerStateDescription),
+		metric.WithInt64Callback(
+			func(ctx context.Context, o metric.Int64Observer) error {
+				o.Observe(stateFn().Value())
+				return nil
+			},
+		),
+	)
+	handleErr(err)
+}
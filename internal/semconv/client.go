@@ -9,8 +9,9 @@ import (
 	"go.opentelemetry.io/otel/metric"
 )
 
-func NewCmdStreamClient[T any](remoteAddr net.Addr,
-	meter metric.Meter) (client CmdStreamClient[T]) {
+func NewCmdStreamClient[T any](remoteAddr net.Addr, meter metric.Meter,
+	limiter *CardinalityLimiter, stability semconv.Stability) (
+	client CmdStreamClient[T]) {
 	var (
 		fn = func(meter metric.Meter) (cmdCounter metric.Int64Counter,
 			resultCounter metric.Int64Counter,
@@ -62,18 +63,24 @@ func NewCmdStreamClient[T any](remoteAddr net.Addr,
 			handleErr(err)
 			return
 		}
-		common = NewCmdStreamCommon[T](meter, fn)
+		common = NewCmdStreamCommon[T](meter, limiter, fn)
 	)
-	return CmdStreamClient[T]{common, AddrAttrs(remoteAddr)}
+	return CmdStreamClient[T]{common, ClientAddrAttrs(remoteAddr, stability),
+		stability}
 }
 
 type CmdStreamClient[T any] struct {
 	CmdStreamCommon[T]
 	addrAttrs []attribute.KeyValue
+	stability semconv.Stability
 }
 
-func (c CmdStreamClient[T]) SpanAttrs(sentCmd hooks.SentCmd[T],
-	addAttrs []attribute.KeyValue) (attrs []attribute.KeyValue) {
+// SpanAttrs returns span attributes. If remoteAddr is nil, the address
+// attributes are taken from the address specified on creation.
+func (c CmdStreamClient[T]) SpanAttrs(remoteAddr net.Addr,
+	sentCmd hooks.SentCmd[T],
+	addAttrs []attribute.KeyValue,
+) (attrs []attribute.KeyValue) {
 	/*
 		net.peer.address
 		net.peer.port
@@ -81,14 +88,18 @@ func (c CmdStreamClient[T]) SpanAttrs(sentCmd hooks.SentCmd[T],
 		cmd-stream.command.seq
 		cmd-stream.command.size
 	*/
+	addrAttrs := c.addrAttrs
+	if remoteAddr != nil {
+		addrAttrs = CachedClientAddrAttrs(remoteAddr, c.stability)
+	}
 	var (
 		l1 = len(addAttrs)
-		l2 = len(c.addrAttrs)
+		l2 = len(addrAttrs)
 		l  = l1 + l2
 	)
 	attrs = make([]attribute.KeyValue, l, l+2)
 	copy(attrs, addAttrs)
-	copy(attrs[l1:], c.addrAttrs)
+	copy(attrs[l1:], addrAttrs)
 	attrs = append(attrs, semconv.CmdStreamCommandSeqKey.Int64(int64(sentCmd.Seq)))
 	attrs = append(attrs, semconv.CmdStreamCommandSizeKey.Int64(int64(sentCmd.Size)))
 	return
@@ -0,0 +1,121 @@
+package semconv
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	otel_semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+)
+
+// ConnectSpanName is the name of the client connection attempt span.
+const ConnectSpanName = "cmd-stream connect"
+
+// ReconnectSpanName is the name of the client reconnect span.
+const ReconnectSpanName = "cmd-stream reconnect"
+
+// ReconnectAttemptEventName is the name of the reconnect span event added for
+// each reconnect attempt.
+const ReconnectAttemptEventName = "reconnect_attempt"
+
+func NewCmdStreamClientConnection(meter metric.Meter) (
+	c CmdStreamClientConnection) {
+	if meter == nil {
+		c.attemptsCounter = noop.Int64Counter{}
+		c.reconnectAttemptsCounter = noop.Int64Counter{}
+		c.reconnectDelayHistogram = noop.Float64Histogram{}
+		c.keepaliveRTTHistogram = noop.Float64Histogram{}
+		c.disconnectedHistogram = noop.Float64Histogram{}
+		return
+	}
+	var err error
+	c.attemptsCounter, err = meter.Int64Counter(
+		semconv.CmdStreamClientConnectionAttemptsName,
+		metric.WithUnit(semconv.CmdStreamClientConnectionAttemptsUnit),
+		metric.WithDescription(semconv.CmdStreamClientConnectionAttemptsDescription),
+	)
+	handleErr(err)
+
+	c.reconnectAttemptsCounter, err = meter.Int64Counter(
+		semconv.CmdStreamClientReconnectAttemptsName,
+		metric.WithUnit(semconv.CmdStreamClientReconnectAttemptsUnit),
+		metric.WithDescription(semconv.CmdStreamClientReconnectAttemptsDescription),
+	)
+	handleErr(err)
+
+	c.reconnectDelayHistogram, err = meter.Float64Histogram(
+		semconv.CmdStreamClientReconnectDelayName,
+		metric.WithUnit(semconv.CmdStreamClientReconnectDelayUnit),
+		metric.WithDescription(semconv.CmdStreamClientReconnectDelayDescription),
+	)
+	handleErr(err)
+
+	c.keepaliveRTTHistogram, err = meter.Float64Histogram(
+		semconv.CmdStreamClientKeepaliveRTTName,
+		metric.WithUnit(semconv.CmdStreamClientKeepaliveRTTUnit),
+		metric.WithDescription(semconv.CmdStreamClientKeepaliveRTTDescription),
+	)
+	handleErr(err)
+
+	c.disconnectedHistogram, err = meter.Float64Histogram(
+		semconv.CmdStreamClientDisconnectedDurationName,
+		metric.WithUnit(semconv.CmdStreamClientDisconnectedDurationUnit),
+		metric.WithDescription(semconv.CmdStreamClientDisconnectedDurationDescription),
+	)
+	handleErr(err)
+	return
+}
+
+type CmdStreamClientConnection struct {
+	attemptsCounter          metric.Int64Counter
+	reconnectAttemptsCounter metric.Int64Counter
+	reconnectDelayHistogram  metric.Float64Histogram
+	keepaliveRTTHistogram    metric.Float64Histogram
+	disconnectedHistogram    metric.Float64Histogram
+}
+
+// RecordAttempt records a connection attempt, err is the attempt error, if
+// any.
+func (c CmdStreamClientConnection) RecordAttempt(ctx context.Context,
+	err error) {
+	c.attemptsCounter.Add(ctx, 1, errMetricOption(err))
+}
+
+// RecordReconnectAttempt records a reconnect attempt and the backoff delay
+// before it, delay is in seconds.
+func (c CmdStreamClientConnection) RecordReconnectAttempt(ctx context.Context,
+	delay float64, err error) {
+	c.reconnectAttemptsCounter.Add(ctx, 1, errMetricOption(err))
+	c.reconnectDelayHistogram.Record(ctx, delay)
+}
+
+// RecordKeepaliveRTT records the round-trip time of the keepalive Ping-Pong
+// exchange, rtt is in seconds.
+func (c CmdStreamClientConnection) RecordKeepaliveRTT(ctx context.Context,
+	rtt float64) {
+	c.keepaliveRTTHistogram.Record(ctx, rtt)
+}
+
+// RecordDisconnected records the time spent disconnected, duration is in
+// seconds, err is the error of the reconnect, if any.
+func (c CmdStreamClientConnection) RecordDisconnected(ctx context.Context,
+	duration float64, err error) {
+	c.disconnectedHistogram.Record(ctx, duration, errMetricOption(err))
+}
+
+// ErrAttrs returns the error.type attribute or nil if err is nil.
+func ErrAttrs(err error) []attribute.KeyValue {
+	if err == nil {
+		return nil
+	}
+	return []attribute.KeyValue{
+		otel_semconv.ErrorTypeKey.String(reflect.TypeOf(err).String()),
+	}
+}
+
+func errMetricOption(err error) metric.MeasurementOption {
+	return metric.WithAttributeSet(attribute.NewSet(ErrAttrs(err)...))
+}
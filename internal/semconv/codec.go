@@ -0,0 +1,87 @@
+package semconv
+
+import (
+	"context"
+
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// EncodedEventName is the name of the span event added when the Command is
+// encoded.
+const EncodedEventName = "encoded"
+
+// DecodedEventName is the name of the span event added when the Command is
+// decoded.
+const DecodedEventName = "decoded"
+
+func NewCmdStreamCodec(meter metric.Meter) (c CmdStreamCodec) {
+	if meter == nil {
+		c.durationHistogram = noop.Float64Histogram{}
+		c.sizeHistogram = noop.Int64Histogram{}
+		c.errorCounter = noop.Int64Counter{}
+		return
+	}
+	var err error
+	c.durationHistogram, err = meter.Float64Histogram(
+		semconv.CmdStreamCodecDurationName,
+		metric.WithUnit(semconv.CmdStreamCodecDurationUnit),
+		metric.WithDescription(semconv.CmdStreamCodecDurationDescription),
+	)
+	handleErr(err)
+
+	c.sizeHistogram, err = meter.Int64Histogram(
+		semconv.CmdStreamCodecSizeName,
+		metric.WithUnit(semconv.CmdStreamCodecSizeUnit),
+		metric.WithDescription(semconv.CmdStreamCodecSizeDescription),
+	)
+	handleErr(err)
+
+	c.errorCounter, err = meter.Int64Counter(
+		semconv.CmdStreamCodecErrorCountName,
+		metric.WithUnit(semconv.CmdStreamCodecErrorCountUnit),
+		metric.WithDescription(semconv.CmdStreamCodecErrorCountDescription),
+	)
+	handleErr(err)
+	return
+}
+
+type CmdStreamCodec struct {
+	durationHistogram metric.Float64Histogram
+	sizeHistogram     metric.Int64Histogram
+	errorCounter      metric.Int64Counter
+}
+
+// Record records the codec operation, duration is in seconds. typeAttr is
+// the type attribute of the Command or Result, it is omitted if invalid. The
+// size is recorded only if err is nil.
+func (c CmdStreamCodec) Record(ctx context.Context,
+	op semconv.CmdStreamCodecOperation, typeAttr attribute.KeyValue, size int,
+	duration float64, err error) {
+	attrs := make([]attribute.KeyValue, 0, 3)
+	attrs = append(attrs, semconv.CmdStreamCodecOperationKey.String(string(op)))
+	if typeAttr.Valid() {
+		attrs = append(attrs, typeAttr)
+	}
+	if err != nil {
+		attrs = append(attrs, ErrAttrs(err)...)
+	}
+	opt := metric.WithAttributeSet(attribute.NewSet(attrs...))
+	c.durationHistogram.Record(ctx, duration, opt)
+	if err != nil {
+		c.errorCounter.Add(ctx, 1, opt)
+		return
+	}
+	c.sizeHistogram.Record(ctx, int64(size), opt)
+}
+
+// CodecEventAttrs returns attributes of the encoded and decoded span events,
+// duration is in seconds.
+func CodecEventAttrs(size int, duration float64) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.CmdStreamCommandSizeKey.Int64(int64(size)),
+		semconv.CmdStreamCodecElapsedTimeKey.Float64(duration),
+	}
+}
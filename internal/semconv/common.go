@@ -4,6 +4,8 @@ import (
 	"context"
 	"net"
 	"reflect"
+	"sync"
+	"sync/atomic"
 
 	"github.com/cmd-stream/cmd-stream-go/core"
 	"github.com/cmd-stream/cmd-stream-go/sender/hooks"
@@ -26,8 +28,10 @@ type unitsFn func(meter metric.Meter) (cmdCounter metric.Int64Counter,
 	resultDurationHistogram metric.Float64Histogram,
 )
 
-func NewCmdStreamCommon[T any](meter metric.Meter, fn unitsFn) (
-	c CmdStreamCommon[T]) {
+func NewCmdStreamCommon[T any](meter metric.Meter, limiter *CardinalityLimiter,
+	fn unitsFn) (c CmdStreamCommon[T]) {
+	c.limiter = limiter
+	c.options = &metricOptionCache{}
 	if meter == nil {
 		c.cmdCounter = noop.Int64Counter{}
 		c.resultCounter = noop.Int64Counter{}
@@ -51,6 +55,9 @@ type CmdStreamCommon[T any] struct {
 
 	cmdDurationHistogram    metric.Float64Histogram
 	resultDurationHistogram metric.Float64Histogram
+
+	limiter *CardinalityLimiter
+	options *metricOptionCache
 }
 
 func (c CmdStreamCommon[T]) RecordCmdMetrics(ctx context.Context,
@@ -59,10 +66,10 @@ func (c CmdStreamCommon[T]) RecordCmdMetrics(ctx context.Context,
 	elapsedTime float64,
 	addAttrs []attribute.KeyValue,
 ) {
-	op := c.cmdMetricOption(sentCmd.Cmd, status, addAttrs)
-	c.cmdCounter.Add(ctx, 1, op)
-	c.cmdSizeHistogram.Record(ctx, int64(sentCmd.Size), op)
-	c.cmdDurationHistogram.Record(ctx, elapsedTime, op)
+	ops := c.cmdMetricOptions(ctx, sentCmd.Cmd, status, addAttrs)
+	c.cmdCounter.Add(ctx, 1, ops.add...)
+	c.cmdSizeHistogram.Record(ctx, int64(sentCmd.Size), ops.record...)
+	c.cmdDurationHistogram.Record(ctx, elapsedTime, ops.record...)
 }
 
 func (c CmdStreamCommon[T]) RecordResultMetrics(ctx context.Context,
@@ -71,10 +78,10 @@ func (c CmdStreamCommon[T]) RecordResultMetrics(ctx context.Context,
 	elapsedTime float64,
 	addAttrs []attribute.KeyValue,
 ) {
-	op := c.resultMetricsOption(sentCmd.Cmd, recvResult.Result, addAttrs)
-	c.resultCounter.Add(ctx, 1, op)
-	c.resultSizeHistogram.Record(ctx, int64(recvResult.Size), op)
-	c.resultDurationHistogram.Record(ctx, elapsedTime, op)
+	ops := c.resultMetricOptions(ctx, sentCmd.Cmd, recvResult.Result, addAttrs)
+	c.resultCounter.Add(ctx, 1, ops.add...)
+	c.resultSizeHistogram.Record(ctx, int64(recvResult.Size), ops.record...)
+	c.resultDurationHistogram.Record(ctx, elapsedTime, ops.record...)
 }
 
 func (c CmdStreamCommon[T]) CmdTypeAttr(cmd core.Cmd[T]) attribute.KeyValue {
@@ -93,37 +100,142 @@ func (c CmdStreamCommon[T]) TypeAttrValue(t reflect.Type) (value string) {
 	return t.String()
 }
 
-func (c CmdStreamCommon[T]) cmdMetricOption(cmd core.Cmd[T],
+func (c CmdStreamCommon[T]) cmdMetricOptions(ctx context.Context,
+	cmd core.Cmd[T],
 	status semconv.CmdStreamCommandStatus,
 	addAttrs []attribute.KeyValue,
-) metric.MeasurementOption {
+) metricOptions {
+	cacheable := c.cacheable(addAttrs)
+	key := metricOptionKey{TypeStr(cmd), string(status)}
+	if cacheable {
+		if ops, ok := c.options.load(key); ok {
+			return ops
+		}
+	}
 	var (
 		l     = len(addAttrs)
 		attrs = make([]attribute.KeyValue, l, l+2)
 	)
 	copy(attrs, addAttrs)
-	attrs = append(attrs, c.CmdTypeAttr(cmd))
-	attrs = append(attrs, semconv.CmdStreamCommandStatusKey.String(string(status)))
-	return metric.WithAttributeSet(attribute.NewSet(attrs...))
+	attrs = append(attrs, semconv.CmdStreamCommandTypeKey.String(key.first))
+	attrs = append(attrs, semconv.CmdStreamCommandStatusKey.String(key.second))
+	c.limiter.Limit(ctx, attrs)
+	ops := newMetricOptions(attribute.NewSet(attrs...))
+	if cacheable {
+		c.options.store(key, ops)
+	}
+	return ops
 }
 
-func (c CmdStreamCommon[T]) resultMetricsOption(cmd core.Cmd[T],
-	result core.Result, addAttrs []attribute.KeyValue) metric.MeasurementOption {
+func (c CmdStreamCommon[T]) resultMetricOptions(ctx context.Context,
+	cmd core.Cmd[T], result core.Result,
+	addAttrs []attribute.KeyValue) metricOptions {
+	cacheable := c.cacheable(addAttrs)
+	key := metricOptionKey{TypeStr(cmd), TypeStr(result)}
+	if cacheable {
+		if ops, ok := c.options.loadResult(key); ok {
+			return ops
+		}
+	}
 	var (
 		l     = len(addAttrs)
 		attrs = make([]attribute.KeyValue, l, l+2)
 	)
 	copy(attrs, addAttrs)
-	attrs = append(attrs, c.CmdTypeAttr(cmd))
-	attrs = append(attrs, c.ResultTypeAttr(result))
-	return metric.WithAttributeSet(attribute.NewSet(attrs...))
+	attrs = append(attrs, semconv.CmdStreamCommandTypeKey.String(key.first))
+	attrs = append(attrs, semconv.CmdStreamResultTypeKey.String(key.second))
+	c.limiter.Limit(ctx, attrs)
+	ops := newMetricOptions(attribute.NewSet(attrs...))
+	if cacheable {
+		c.options.storeResult(key, ops)
+	}
+	return ops
+}
+
+// cacheable reports whether the metric options can be cached. It can't if there
+// are additional attributes or the cardinality limiter, which counts
+// overflows, is set.
+func (c CmdStreamCommon[T]) cacheable(addAttrs []attribute.KeyValue) bool {
+	return len(addAttrs) == 0 && c.limiter == nil && c.options != nil
+}
+
+// metricOptions holds the measurement options of Add and Record calls, so
+// they are not allocated on each call.
+type metricOptions struct {
+	add    []metric.AddOption
+	record []metric.RecordOption
+}
+
+func newMetricOptions(set attribute.Set) metricOptions {
+	op := metric.WithAttributeSet(set)
+	return metricOptions{
+		add:    []metric.AddOption{op},
+		record: []metric.RecordOption{op},
+	}
+}
+
+// metricOptionKey is the (Command type, status) or (Command type, Result type)
+// pair.
+type metricOptionKey struct {
+	first, second string
+}
+
+// metricOptionCacheSize is the maximum number of entries in each of the
+// metricOptionCache maps. Command and Result type names come from TypeStr,
+// which can be implemented by users, so their number is not limited.
+const metricOptionCacheSize = 1024
+
+// metricOptionCache caches metric options of the Command and Result metrics.
+// Once a map holds metricOptionCacheSize entries, new keys are not cached.
+type metricOptionCache struct {
+	cmd       sync.Map
+	cmdLen    atomic.Int64
+	result    sync.Map
+	resultLen atomic.Int64
+}
+
+func (c *metricOptionCache) load(key metricOptionKey) (ops metricOptions,
+	ok bool) {
+	v, ok := c.cmd.Load(key)
+	if !ok {
+		return
+	}
+	return v.(metricOptions), true
+}
+
+func (c *metricOptionCache) store(key metricOptionKey, ops metricOptions) {
+	storeBounded(&c.cmd, &c.cmdLen, key, ops)
+}
+
+func (c *metricOptionCache) loadResult(key metricOptionKey) (
+	ops metricOptions, ok bool) {
+	v, ok := c.result.Load(key)
+	if !ok {
+		return
+	}
+	return v.(metricOptions), true
+}
+
+func (c *metricOptionCache) storeResult(key metricOptionKey,
+	ops metricOptions) {
+	storeBounded(&c.result, &c.resultLen, key, ops)
+}
+
+func storeBounded(m *sync.Map, l *atomic.Int64, key metricOptionKey,
+	ops metricOptions) {
+	if l.Load() >= metricOptionCacheSize {
+		return
+	}
+	if _, loaded := m.LoadOrStore(key, ops); !loaded {
+		l.Add(1)
+	}
 }
 
 func TypeStr(a any) (str string) {
 	if typedCmd, ok := a.(typed); ok {
 		return typedCmd.TypeStr()
 	}
-	return reflect.TypeOf(a).Name()
+	return typeName(reflect.TypeOf(a))
 }
 
 func AddrAttrs(addr net.Addr) []attribute.KeyValue {
@@ -0,0 +1,70 @@
+package semconv
+
+import (
+	"context"
+
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// ConnSpanName is the name of the connection span.
+const ConnSpanName = "cmd-stream connection"
+
+// ServerInfoEventName is the name of the connection span event added when the
+// ServerInfo is sent to the client.
+const ServerInfoEventName = "server_info"
+
+func NewCmdStreamServerConnection(meter metric.Meter) (
+	c CmdStreamServerConnection) {
+	if meter == nil {
+		c.activeCounter = noop.Int64UpDownCounter{}
+		c.countCounter = noop.Int64Counter{}
+		c.durationHistogram = noop.Float64Histogram{}
+		return
+	}
+	var err error
+	c.activeCounter, err = meter.Int64UpDownCounter(
+		semconv.CmdStreamServerConnectionActiveName,
+		metric.WithUnit(semconv.CmdStreamServerConnectionActiveUnit),
+		metric.WithDescription(semconv.CmdStreamServerConnectionActiveDescription),
+	)
+	handleErr(err)
+
+	c.countCounter, err = meter.Int64Counter(
+		semconv.CmdStreamServerConnectionCountName,
+		metric.WithUnit(semconv.CmdStreamServerConnectionCountUnit),
+		metric.WithDescription(semconv.CmdStreamServerConnectionCountDescription),
+	)
+	handleErr(err)
+
+	c.durationHistogram, err = meter.Float64Histogram(
+		semconv.CmdStreamServerConnectionDurationName,
+		metric.WithUnit(semconv.CmdStreamServerConnectionDurationUnit),
+		metric.WithDescription(semconv.CmdStreamServerConnectionDurationDescription),
+	)
+	handleErr(err)
+	return
+}
+
+type CmdStreamServerConnection struct {
+	activeCounter     metric.Int64UpDownCounter
+	countCounter      metric.Int64Counter
+	durationHistogram metric.Float64Histogram
+}
+
+func (c CmdStreamServerConnection) RecordOpen(ctx context.Context) {
+	c.activeCounter.Add(ctx, 1)
+}
+
+// RecordClose records the closed connection, duration is in seconds.
+func (c CmdStreamServerConnection) RecordClose(ctx context.Context,
+	reason semconv.CmdStreamConnectionCloseReason, duration float64) {
+	c.activeCounter.Add(ctx, -1)
+	op := metric.WithAttributeSet(attribute.NewSet(
+		semconv.CmdStreamConnectionCloseReasonKey.String(string(reason)),
+	))
+	c.countCounter.Add(ctx, 1, op)
+	c.durationHistogram.Record(ctx, duration, op)
+}
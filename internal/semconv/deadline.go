@@ -0,0 +1,44 @@
+package semconv
+
+import (
+	"context"
+
+	"github.com/cmd-stream/cmd-stream-go/core"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+func NewCmdStreamServerDeadline[T any](meter metric.Meter) (
+	d CmdStreamServerDeadline[T]) {
+	if meter == nil {
+		d.expiredCounter = noop.Int64Counter{}
+		return
+	}
+	var err error
+	d.expiredCounter, err = meter.Int64Counter(
+		semconv.CmdStreamServerCommandExpiredCountName,
+		metric.WithUnit(semconv.CmdStreamServerCommandExpiredCountUnit),
+		metric.WithDescription(semconv.CmdStreamServerCommandExpiredCountDescription),
+	)
+	handleErr(err)
+	return
+}
+
+type CmdStreamServerDeadline[T any] struct {
+	expiredCounter metric.Int64Counter
+}
+
+func (d CmdStreamServerDeadline[T]) RecordExpired(ctx context.Context,
+	cmd core.Cmd[T],
+	addAttrs []attribute.KeyValue,
+) {
+	var (
+		l     = len(addAttrs)
+		attrs = make([]attribute.KeyValue, l, l+1)
+	)
+	copy(attrs, addAttrs)
+	attrs = append(attrs, semconv.CmdStreamCommandTypeKey.String(TypeStr(cmd)))
+	d.expiredCounter.Add(ctx, 1, metric.WithAttributeSet(attribute.NewSet(attrs...)))
+}
@@ -0,0 +1,40 @@
+package semconv
+
+import (
+	"context"
+
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+func NewAttributePolicyViolations(meter metric.Meter) (
+	v AttributePolicyViolations) {
+	if meter == nil {
+		v.counter = noop.Int64Counter{}
+		return
+	}
+	var err error
+	v.counter, err = meter.Int64Counter(
+		semconv.CmdStreamAttributePolicyViolationCountName,
+		metric.WithUnit(semconv.CmdStreamAttributePolicyViolationCountUnit),
+		metric.WithDescription(semconv.CmdStreamAttributePolicyViolationCountDescription),
+	)
+	handleErr(err)
+	return
+}
+
+type AttributePolicyViolations struct {
+	counter metric.Int64Counter
+}
+
+// Record counts the violation. The key of the violating attribute is not
+// recorded, because it is set by the user and so is unbounded.
+func (v AttributePolicyViolations) Record(ctx context.Context,
+	violation semconv.CmdStreamAttributeViolation,
+) {
+	v.counter.Add(ctx, 1, metric.WithAttributeSet(attribute.NewSet(
+		semconv.CmdStreamAttributeViolationKey.String(string(violation)),
+	)))
+}
@@ -0,0 +1,91 @@
+package semconv
+
+import (
+	"context"
+	"time"
+
+	"github.com/cmd-stream/cmd-stream-go/core"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// ResultSendErrorEventName is the name of the server span event added when
+// the Result fails to be sent.
+const ResultSendErrorEventName = "result_send_error"
+
+func NewCmdStreamServerResultSend[T any](meter metric.Meter) (
+	s CmdStreamServerResultSend[T]) {
+	if meter == nil {
+		s.errorCounter = noop.Int64Counter{}
+		return
+	}
+	var err error
+	s.errorCounter, err = meter.Int64Counter(
+		semconv.CmdStreamServerResultSendErrorCountName,
+		metric.WithUnit(semconv.CmdStreamServerResultSendErrorCountUnit),
+		metric.WithDescription(semconv.CmdStreamServerResultSendErrorCountDescription),
+	)
+	handleErr(err)
+	return
+}
+
+type CmdStreamServerResultSend[T any] struct {
+	errorCounter metric.Int64Counter
+}
+
+// RecordSendError records a failed send of the Result of the Command.
+func (s CmdStreamServerResultSend[T]) RecordSendError(ctx context.Context,
+	cmd core.Cmd[T],
+	err error,
+	deadlineExceeded bool,
+	addAttrs []attribute.KeyValue,
+) {
+	var (
+		l     = len(addAttrs)
+		attrs = make([]attribute.KeyValue, l, l+3)
+	)
+	copy(attrs, addAttrs)
+	attrs = append(attrs, semconv.CmdStreamCommandTypeKey.String(TypeStr(cmd)))
+	attrs = append(attrs, ResultSendErrorAttrs(err, deadlineExceeded)...)
+	s.errorCounter.Add(ctx, 1, metric.WithAttributeSet(attribute.NewSet(attrs...)))
+}
+
+// ResultSendErrorAttrs returns attributes of the failed Result send.
+func ResultSendErrorAttrs(err error, deadlineExceeded bool) []attribute.KeyValue {
+	return append(ErrAttrs(err),
+		semconv.CmdStreamResultSendDeadlineExceededKey.Bool(deadlineExceeded))
+}
+
+// ResultSendSpanName returns the name of the server span that covers sending
+// of the Result.
+func ResultSendSpanName(result core.Result) string {
+	if result == nil {
+		return "Send result"
+	}
+	return "Send result " + TypeStr(result)
+}
+
+// ResultSendSpanAttrs returns attributes of the result send span, resultSeq
+// and deadline are omitted if zero.
+func ResultSendSpanAttrs(cmdSeq, resultSeq core.Seq, result core.Result,
+	size int, elapsedTime float64, deadline time.Time) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		semconv.CmdStreamCommandSeqKey.Int64(int64(cmdSeq)),
+		semconv.CmdStreamResultSizeKey.Int(size),
+		semconv.CmdStreamResultSendElapsedTimeKey.Float64(elapsedTime),
+	}
+	if resultSeq != 0 {
+		attrs = append(attrs, semconv.CmdStreamResultSeqKey.Int64(int64(resultSeq)))
+	}
+	if result != nil {
+		attrs = append(attrs, semconv.CmdStreamResultTypeKey.String(TypeStr(result)))
+	}
+	if !deadline.IsZero() {
+		attrs = append(attrs,
+			semconv.CmdStreamResultSendDeadlineKey.String(
+				deadline.UTC().Format(time.RFC3339Nano)))
+	}
+	return attrs
+}
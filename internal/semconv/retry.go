@@ -0,0 +1,37 @@
+package semconv
+
+import (
+	"context"
+
+	"github.com/cmd-stream/cmd-stream-go/core"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+func NewCmdStreamRetry[T any](meter metric.Meter) (r CmdStreamRetry[T]) {
+	if meter == nil {
+		r.retryCounter = noop.Int64Counter{}
+		return
+	}
+	var err error
+	r.retryCounter, err = meter.Int64Counter(
+		semconv.CmdStreamClientCommandRetryCountName,
+		metric.WithUnit(semconv.CmdStreamClientCommandRetryCountUnit),
+		metric.WithDescription(semconv.CmdStreamClientCommandRetryCountDescription),
+	)
+	handleErr(err)
+	return
+}
+
+type CmdStreamRetry[T any] struct {
+	retryCounter metric.Int64Counter
+}
+
+// RecordRetry records a retry of the Command.
+func (r CmdStreamRetry[T]) RecordRetry(ctx context.Context, cmd core.Cmd[T]) {
+	r.retryCounter.Add(ctx, 1, metric.WithAttributeSet(attribute.NewSet(
+		semconv.CmdStreamCommandTypeKey.String(TypeStr(cmd)),
+	)))
+}
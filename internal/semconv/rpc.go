@@ -0,0 +1,88 @@
+package semconv
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/cmd-stream/cmd-stream-go/core"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	otel_semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+)
+
+// RPCSystem is the rpc.system attribute value.
+const RPCSystem = "cmd-stream"
+
+// rpcDurationUnit differs from the "ms" of the v1.30.0 conventions, newer
+// versions of the RPC conventions use seconds.
+const rpcDurationUnit = "s"
+
+func NewRPCClient[T any](meter metric.Meter,
+	limiter *CardinalityLimiter) RPC[T] {
+	return newRPC[T](meter, limiter, otel_semconv.RPCClientDurationName,
+		otel_semconv.RPCClientDurationDescription)
+}
+
+func NewRPCServer[T any](meter metric.Meter,
+	limiter *CardinalityLimiter) RPC[T] {
+	return newRPC[T](meter, limiter, otel_semconv.RPCServerDurationName,
+		otel_semconv.RPCServerDurationDescription)
+}
+
+func newRPC[T any](meter metric.Meter, limiter *CardinalityLimiter,
+	name, description string) (r RPC[T]) {
+	r.service = ReceiverTypeStr[T]()
+	r.limiter = limiter
+	if meter == nil {
+		r.durationHistogram = noop.Float64Histogram{}
+		return
+	}
+	var err error
+	r.durationHistogram, err = meter.Float64Histogram(name,
+		metric.WithUnit(rpcDurationUnit),
+		metric.WithDescription(description),
+	)
+	handleErr(err)
+	return
+}
+
+// RPC provides attributes and metrics conforming to the OpenTelemetry RPC
+// semantic conventions.
+type RPC[T any] struct {
+	service           string
+	limiter           *CardinalityLimiter
+	durationHistogram metric.Float64Histogram
+}
+
+// Attrs returns rpc.system, rpc.service and rpc.method attributes for the
+// Command.
+func (r RPC[T]) Attrs(cmd core.Cmd[T]) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		otel_semconv.RPCSystemKey.String(RPCSystem),
+		otel_semconv.RPCService(r.service),
+		otel_semconv.RPCMethod(TypeStr(cmd)),
+	}
+}
+
+// RecordDuration records the duration, in milliseconds, as seconds. The
+// attributes go through the cardinality limiter, like the cmd-stream metric
+// attributes do.
+func (r RPC[T]) RecordDuration(ctx context.Context, cmd core.Cmd[T],
+	status semconv.CmdStreamCommandStatus, elapsedTime float64) {
+	attrs := append(r.Attrs(cmd),
+		semconv.CmdStreamCommandStatusKey.String(string(status)))
+	r.limiter.Limit(ctx, attrs)
+	r.durationHistogram.Record(ctx, elapsedTime/1000,
+		metric.WithAttributeSet(attribute.NewSet(attrs...)))
+}
+
+// ReceiverTypeStr returns the name of the receiver type.
+func ReceiverTypeStr[T any]() string {
+	t := reflect.TypeFor[T]()
+	if t.Name() != "" {
+		return t.Name()
+	}
+	return t.String()
+}
@@ -3,14 +3,16 @@ package semconv
 import (
 	"net"
 
+	"github.com/cmd-stream/cmd-stream-go/core"
 	"github.com/cmd-stream/cmd-stream-go/sender/hooks"
 	"github.com/cmd-stream/otelcmd-stream-go/semconv"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 )
 
-func NewCmdStreamServer[T any](localAddr net.Addr,
-	meter metric.Meter) (client CmdStreamServer[T]) {
+func NewCmdStreamServer[T any](localAddr net.Addr, meter metric.Meter,
+	limiter *CardinalityLimiter, stability semconv.Stability) (
+	client CmdStreamServer[T]) {
 	var (
 		fn = func(meter metric.Meter) (cmdCounter metric.Int64Counter,
 			resultCounter metric.Int64Counter,
@@ -62,13 +64,14 @@ func NewCmdStreamServer[T any](localAddr net.Addr,
 			handleErr(err)
 			return
 		}
-		common = NewCmdStreamCommon[T](meter, fn)
+		common = NewCmdStreamCommon[T](meter, limiter, fn)
 	)
-	return CmdStreamServer[T]{common}
+	return CmdStreamServer[T]{common, stability}
 }
 
 type CmdStreamServer[T any] struct {
 	CmdStreamCommon[T]
+	stability semconv.Stability
 }
 
 func (c CmdStreamServer[T]) SpanAttrs(remoteAddr net.Addr,
@@ -79,7 +82,7 @@ func (c CmdStreamServer[T]) SpanAttrs(remoteAddr net.Addr,
 		network.protocol.name
 	*/
 	var (
-		addrAttrs = AddrAttrs(remoteAddr)
+		addrAttrs = CachedServerAddrAttrs(remoteAddr, c.stability)
 		l1        = len(addAttrs)
 		l2        = len(addrAttrs)
 	)
@@ -91,17 +94,20 @@ func (c CmdStreamServer[T]) SpanAttrs(remoteAddr net.Addr,
 
 func (c CmdStreamServer[T]) SpanResultEventAttrs(sentCmd hooks.SentCmd[T],
 	recvResult hooks.ReceivedResult,
+	cmdSeq core.Seq,
 	addAttrs []attribute.KeyValue,
 ) (attrs []attribute.KeyValue) {
 	/*
 		cmd-stream.result.seq
+		cmd-stream.command.seq
 		cmd-stream.result.size
 		cmd-stream.result.type
 	*/
 	l := len(addAttrs)
-	attrs = make([]attribute.KeyValue, l, l+3)
+	attrs = make([]attribute.KeyValue, l, l+4)
 	copy(attrs, addAttrs)
 	attrs = append(attrs, semconv.CmdStreamResultSeqKey.Int64(int64(recvResult.Seq)))
+	attrs = append(attrs, semconv.CmdStreamCommandSeqKey.Int64(int64(cmdSeq)))
 	attrs = append(attrs, semconv.CmdStreamResultSizeKey.Int64(int64(recvResult.Size)))
 	attrs = append(attrs, c.ResultTypeAttr(recvResult.Result))
 	return
@@ -28,48 +28,101 @@ func NewInvoker[T any](invoker handler.Invoker[T], ops ...SetOption[T]) Invoker[
 		Propagator:        otel.GetTextMapPropagator(),
 		TracerProvider:    otel.GetTracerProvider(),
 		MeterProvider:     otel.GetMeterProvider(),
+		BaggageLimits:     DefaultBaggageLimits,
+		SemconvStability:  semconv.StabilityFromEnv(),
 	}
 	Apply(ops, &o)
-	return Invoker[T]{
+	i := Invoker[T]{
 		invoker: invoker,
-		semconv: internal_semconv.NewCmdStreamServer[T](o.ServerAddr, o.Meter),
+		semconv: internal_semconv.NewCmdStreamServer[T](o.ServerAddr, o.Meter,
+			o.cardinalityLimiter, o.SemconvStability),
+		send:    internal_semconv.NewCmdStreamServerResultSend[T](o.Meter),
 		options: o,
 	}
+	if o.DeadlinePropagation {
+		i.deadline = internal_semconv.NewCmdStreamServerDeadline[T](o.Meter)
+	}
+	if o.RPCSemconv {
+		i.rpc = internal_semconv.NewRPCServer[T](o.Meter,
+			o.cardinalityLimiter)
+	}
+	return i
 }
 
 // Invoker is an implementation of the handler.Invoker interface from the
 // handler module. It adds OpenTelemetry-based instrumentation for command
 // handling on the server side.
 type Invoker[T any] struct {
-	invoker handler.Invoker[T]
-	semconv internal_semconv.CmdStreamServer[T]
-	options Options[T]
+	invoker  handler.Invoker[T]
+	semconv  internal_semconv.CmdStreamServer[T]
+	deadline internal_semconv.CmdStreamServerDeadline[T]
+	send     internal_semconv.CmdStreamServerResultSend[T]
+	rpc      internal_semconv.RPC[T]
+	options  Options[T]
 }
 
 func (i Invoker[T]) Invoke(ctx context.Context, seq core.Seq, at time.Time,
 	bytesRead int, cmd core.Cmd[T], proxy core.Proxy) (err error) {
 	startTime := time.Now()
 
+	var links []trace.Link
 	if tcmd, ok := cmd.(traceCmd[T]); ok {
 		ctx = i.options.Propagator.Extract(ctx, propagation.MapCarrier(tcmd.Carrier()))
+		links = extractRetryLinks(tcmd.Carrier())
 	}
 	// TODO
 	// if startTime := StartTimeFromContext(ctx); !startTime.IsZero() {
 	// 	opts = append(opts, trace.WithTimestamp(startTime))
 	// 	requestStartTime = startTime
 	// }
+	var (
+		remoteAddr = proxy.RemoteAddr()
+		opts       = i.options.SpanStartOptions
+	)
+	if i.options.ConnSpanContexts != nil {
+		if sc, ok := i.options.ConnSpanContexts.ConnSpanContext(remoteAddr); ok {
+			opts = append(opts[:len(opts):len(opts)],
+				trace.WithLinks(trace.Link{SpanContext: sc}))
+		}
+	}
+	if len(links) > 0 {
+		opts = append(opts[:len(opts):len(opts)], trace.WithLinks(links...))
+	}
 	ctx, span := i.options.Tracer.Start(ctx, i.options.SpanNameFormatter(cmd),
-		i.options.SpanStartOptions...)
+		opts...)
+	addDecodedEvent(cmd, span)
 	sentCmd := hooks.SentCmd[T]{Seq: seq, Size: bytesRead, Cmd: cmd}
-	i.setSpanAttributes(span, proxy.RemoteAddr(), sentCmd)
+	i.setSpanAttributes(ctx, span, remoteAddr, sentCmd)
+
+	ctx, cancel, expired := i.withPropagatedDeadline(ctx, cmd, at, startTime)
+	defer cancel()
+	if expired {
+		span.SetAttributes(
+			semconv.CmdStreamCommandStatusKey.String(string(semconv.Expired)))
+		if i.options.SkipExpired {
+			i.recordExpired(ctx, span, sentCmd, ElapsedTime(startTime))
+			return
+		}
+		i.deadline.RecordExpired(ctx, sentCmd.Cmd,
+			i.options.baggageMetricAttributes(ctx))
+	}
 
 	var (
-		callback = func(recvResult hooks.ReceivedResult) {
-			i.setSpanResultEventAttributes(span, sentCmd, recvResult)
+		callback = func(recvResult hooks.ReceivedResult, cmdSeq core.Seq) {
+			i.setSpanResultEventAttributes(ctx, span, sentCmd, recvResult, cmdSeq)
 			i.recordResultMetrics(ctx, sentCmd, recvResult, ElapsedTime(startTime))
 		}
-		proxyWrap = NewProxy[T](proxy, callback)
+		errCallback = func(result core.Result, err error, deadlineExceeded bool) {
+			i.recordSendError(ctx, span, sentCmd, err, deadlineExceeded)
+		}
+		proxyWrap = NewProxy[T](proxy, callback).WithErrorCallback(errCallback)
 	)
+	if i.options.ResultSendSpans {
+		proxyWrap.withSendSpans(ctx, i.options)
+	}
+	if i.options.PanicRecovery != NoPanicRecovery {
+		defer i.recoverPanic(ctx, span, sentCmd, startTime, &err)
+	}
 	err = i.invoker.Invoke(ctx, seq, at, bytesRead, cmd, proxyWrap)
 
 	status := semconv.Ok
@@ -78,30 +131,55 @@ func (i Invoker[T]) Invoke(ctx context.Context, seq core.Seq, at time.Time,
 		if errAttr := i.semconv.ErrorTypeAttr(err); errAttr.Valid() {
 			span.SetAttributes(errAttr)
 		}
-		span.SetStatus(codes.Error, err.Error())
+		i.options.recordError(span, err)
+		span.SetStatus(codes.Error, i.options.errorDescription(err))
+	}
+	if expired {
+		status = semconv.Expired
 	}
 	i.recordCmdMetrics(ctx, sentCmd, status, ElapsedTime(startTime))
 	span.End()
 	return
 }
 
-func (i Invoker[T]) setSpanAttributes(span trace.Span, remoteAddr net.Addr,
-	sentCmd hooks.SentCmd[T]) {
-	var addAttrs []attribute.KeyValue
-	if i.options.SpanAttributesFn != nil {
-		addAttrs = i.options.SpanAttributesFn(remoteAddr, sentCmd)
+func (i Invoker[T]) recordExpired(ctx context.Context, span trace.Span,
+	sentCmd hooks.SentCmd[T], elapsedTime float64) {
+	err := context.DeadlineExceeded
+	if errAttr := i.semconv.ErrorTypeAttr(err); errAttr.Valid() {
+		span.SetAttributes(errAttr)
 	}
-	span.SetAttributes(i.semconv.SpanAttrs(remoteAddr, addAttrs)...)
+	i.options.recordError(span, err)
+	span.SetStatus(codes.Error, i.options.errorDescription(err))
+	i.recordCmdMetrics(ctx, sentCmd, semconv.Expired, elapsedTime)
+	i.deadline.RecordExpired(ctx, sentCmd.Cmd,
+		i.options.baggageMetricAttributes(ctx))
+	span.End()
+}
+
+func (i Invoker[T]) recordSendError(ctx context.Context, span trace.Span,
+	sentCmd hooks.SentCmd[T], err error, deadlineExceeded bool) {
+	span.AddEvent(internal_semconv.ResultSendErrorEventName, trace.WithAttributes(
+		internal_semconv.ResultSendErrorAttrs(err, deadlineExceeded)...))
+	i.send.RecordSendError(ctx, sentCmd.Cmd, err, deadlineExceeded,
+		i.options.baggageMetricAttributes(ctx))
 }
 
-func (i Invoker[T]) setSpanResultEventAttributes(span trace.Span,
-	sentCmd hooks.SentCmd[T], recvResult hooks.ReceivedResult) {
-	var addAttrs []attribute.KeyValue
-	if i.options.SpanResultEventAttributesFn != nil {
-		addAttrs = i.options.SpanResultEventAttributesFn(sentCmd, recvResult)
+func (i Invoker[T]) setSpanAttributes(ctx context.Context, span trace.Span,
+	remoteAddr net.Addr, sentCmd hooks.SentCmd[T]) {
+	addAttrs := i.options.userSpanAttributes(ctx, remoteAddr, sentCmd)
+	addAttrs = append(addAttrs, i.options.baggageAttributes(ctx)...)
+	if i.options.RPCSemconv {
+		addAttrs = append(addAttrs, i.rpc.Attrs(unwrapCmd(sentCmd.Cmd))...)
 	}
+	span.SetAttributes(i.semconv.SpanAttrs(remoteAddr, addAttrs)...)
+}
+
+func (i Invoker[T]) setSpanResultEventAttributes(ctx context.Context,
+	span trace.Span, sentCmd hooks.SentCmd[T], recvResult hooks.ReceivedResult,
+	cmdSeq core.Seq) {
+	addAttrs, _ := i.options.userResultEventAttributes(ctx, sentCmd, recvResult)
 	span.AddEvent(internal_semconv.ResultEventName, trace.WithAttributes(
-		i.semconv.SpanResultEventAttrs(sentCmd, recvResult, addAttrs)...,
+		i.semconv.SpanResultEventAttrs(sentCmd, recvResult, cmdSeq, addAttrs)...,
 	))
 
 }
@@ -110,12 +188,16 @@ func (i Invoker[T]) recordCmdMetrics(ctx context.Context,
 	sentCmd hooks.SentCmd[T],
 	status semconv.CmdStreamCommandStatus,
 	elapsedTime float64,
+	errAttrs ...attribute.KeyValue,
 ) {
-	var addAttrs []attribute.KeyValue
-	if i.options.CmdMetricAttributesFn != nil {
-		addAttrs = i.options.CmdMetricAttributesFn(sentCmd, status, elapsedTime)
-	}
+	addAttrs := i.options.userCmdMetricAttributes(ctx, sentCmd, status,
+		elapsedTime)
+	addAttrs = append(addAttrs, i.options.baggageMetricAttributes(ctx)...)
+	addAttrs = append(addAttrs, errAttrs...)
 	i.semconv.RecordCmdMetrics(ctx, sentCmd, status, elapsedTime, addAttrs)
+	if i.options.RPCSemconv {
+		i.rpc.RecordDuration(ctx, unwrapCmd(sentCmd.Cmd), status, elapsedTime)
+	}
 }
 
 func (i Invoker[T]) recordResultMetrics(ctx context.Context,
@@ -123,9 +205,8 @@ func (i Invoker[T]) recordResultMetrics(ctx context.Context,
 	recvResult hooks.ReceivedResult,
 	elapsedTime float64,
 ) {
-	var addAttrs []attribute.KeyValue
-	if i.options.ResultMetricAttributesFn != nil {
-		addAttrs = i.options.ResultMetricAttributesFn(sentCmd, recvResult, elapsedTime)
-	}
+	addAttrs := i.options.userResultMetricAttributes(ctx, sentCmd, recvResult,
+		elapsedTime)
+	addAttrs = append(addAttrs, i.options.baggageMetricAttributes(ctx)...)
 	i.semconv.RecordResultMetrics(ctx, sentCmd, recvResult, elapsedTime, addAttrs)
 }
@@ -314,7 +314,15 @@ func mockServerMeterProvider(meterProvider mock.MeterProvider, t *testing.T) (
 		RegisterFloat64Histogram(fn3).
 		RegisterInt64Counter(fn4).
 		RegisterInt64Histogram(fn5).
-		RegisterFloat64Histogram(fn6)
+		RegisterFloat64Histogram(fn6).
+		RegisterInt64Counter(
+			func(name string, options ...metric.Int64CounterOption) (
+				metric.Int64Counter, error) {
+				asserterror.Equal(t, name,
+					semconv.CmdStreamServerResultSendErrorCountName)
+				return mock.NewInt64Counter(), nil
+			},
+		)
 	meterProvider.RegisterMeter(
 		func(name string, opts ...metric.MeterOption) metric.Meter {
 			// TODO
@@ -620,6 +628,7 @@ func newWantVals(addr *net.TCPAddr, spanName string, cmd core.Cmd[any],
 		resultEventOps = append(resultEventOps,
 			trace.WithAttributes(
 				semconv.CmdStreamResultSeqKey.Int64(ResultSeq),
+				semconv.CmdStreamCommandSeqKey.Int64(0),
 				semconv.CmdStreamResultSizeKey.Int64(ResultSize),
 				semconv.CmdStreamResultTypeKey.String(internal_semconv.TypeStr(result)),
 			))
@@ -0,0 +1,228 @@
+package otelcmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cmd-stream/cmd-stream-go/core"
+	internal_semconv "github.com/cmd-stream/otelcmd-stream-go/internal/semconv"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ConnOptions configures Listener.
+type ConnOptions struct {
+	TracerProvider   trace.TracerProvider
+	MeterProvider    metric.MeterProvider
+	ConnSpan         bool
+	SemconvStability semconv.Stability
+}
+
+type SetConnOption func(o *ConnOptions)
+
+// WithConnTracerProvider sets the OpenTelemetry TracerProvider for connection
+// spans.
+func WithConnTracerProvider(tp trace.TracerProvider) SetConnOption {
+	return func(o *ConnOptions) {
+		o.TracerProvider = tp
+	}
+}
+
+// WithConnMeterProvider sets the OpenTelemetry MeterProvider for connection
+// metrics.
+func WithConnMeterProvider(mp metric.MeterProvider) SetConnOption {
+	return func(o *ConnOptions) {
+		o.MeterProvider = mp
+	}
+}
+
+// WithConnSpan enables one long-lived span per connection. Command spans can
+// link to it, see the WithConnSpanLinks option.
+func WithConnSpan() SetConnOption {
+	return func(o *ConnOptions) {
+		o.ConnSpan = true
+	}
+}
+
+// WithConnSemconvStability sets which address attributes are added to
+// connection spans.
+func WithConnSemconvStability(stability semconv.Stability) SetConnOption {
+	return func(o *ConnOptions) {
+		o.SemconvStability = stability
+	}
+}
+
+// ConnSpanContexts provides span contexts of connection spans.
+type ConnSpanContexts interface {
+	ConnSpanContext(remoteAddr net.Addr) (sc trace.SpanContext, ok bool)
+}
+
+// NewListener creates a new Listener.
+func NewListener(listener core.Listener, ops ...SetConnOption) *Listener {
+	o := ConnOptions{
+		TracerProvider:   otel.GetTracerProvider(),
+		MeterProvider:    otel.GetMeterProvider(),
+		SemconvStability: semconv.StabilityFromEnv(),
+	}
+	for i := range ops {
+		if ops[i] != nil {
+			ops[i](&o)
+		}
+	}
+	var meter metric.Meter
+	if o.MeterProvider != nil {
+		meter = o.MeterProvider.Meter(ScopeName,
+			metric.WithInstrumentationVersion(Version()))
+	}
+	l := &Listener{
+		Listener: listener,
+		semconv:  internal_semconv.NewCmdStreamServerConnection(meter),
+		options:  o,
+	}
+	if o.ConnSpan && o.TracerProvider != nil {
+		l.tracer = newTracer(o.TracerProvider)
+	}
+	return l
+}
+
+// Listener wraps a core.Listener and instruments accepted connections. It
+// records the cmd-stream.server.connection.active, .count and .duration
+// metrics, the last two with the close reason attribute, and, optionally, one
+// span per connection.
+//
+// Use it with the core/srv.Server.Serve method.
+type Listener struct {
+	core.Listener
+	semconv internal_semconv.CmdStreamServerConnection
+	tracer  trace.Tracer
+	spans   sync.Map
+	options ConnOptions
+}
+
+func (l *Listener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return conn, err
+	}
+	c := &instrumentedConn{
+		Conn:      conn,
+		listener:  l,
+		startTime: time.Now(),
+	}
+	ctx := context.Background()
+	if l.tracer != nil {
+		_, c.span = l.tracer.Start(ctx, internal_semconv.ConnSpanName,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(internal_semconv.ServerAddrAttrs(conn.RemoteAddr(),
+				l.options.SemconvStability)...),
+		)
+		l.spans.Store(addrKey(conn.RemoteAddr()), c.span.SpanContext())
+	}
+	l.semconv.RecordOpen(ctx)
+	return c, nil
+}
+
+// ConnSpanContext returns the span context of the span of the connection with
+// the specified remote address.
+func (l *Listener) ConnSpanContext(remoteAddr net.Addr) (
+	sc trace.SpanContext, ok bool) {
+	v, ok := l.spans.Load(addrKey(remoteAddr))
+	if !ok {
+		return
+	}
+	return v.(trace.SpanContext), true
+}
+
+type instrumentedConn struct {
+	net.Conn
+	listener  *Listener
+	startTime time.Time
+	span      trace.Span
+	infoSent  atomic.Bool
+	mu        sync.Mutex
+	err       error
+	once      sync.Once
+}
+
+func (c *instrumentedConn) Read(b []byte) (n int, err error) {
+	n, err = c.Conn.Read(b)
+	if err != nil {
+		c.setErr(err)
+	}
+	return
+}
+
+func (c *instrumentedConn) Write(b []byte) (n int, err error) {
+	n, err = c.Conn.Write(b)
+	if err != nil {
+		c.setErr(err)
+		return
+	}
+	// The server starts the connection by sending ServerInfo.
+	if c.span != nil && c.infoSent.CompareAndSwap(false, true) {
+		c.span.AddEvent(internal_semconv.ServerInfoEventName)
+	}
+	return
+}
+
+func (c *instrumentedConn) Close() (err error) {
+	err = c.Conn.Close()
+	c.once.Do(c.closed)
+	return
+}
+
+func (c *instrumentedConn) setErr(err error) {
+	c.mu.Lock()
+	if c.err == nil {
+		c.err = err
+	}
+	c.mu.Unlock()
+}
+
+func (c *instrumentedConn) closed() {
+	c.mu.Lock()
+	reason := closeReason(c.err)
+	c.mu.Unlock()
+
+	ctx := context.Background()
+	c.listener.semconv.RecordClose(ctx, reason,
+		time.Since(c.startTime).Seconds())
+	if c.span != nil {
+		c.listener.spans.Delete(addrKey(c.RemoteAddr()))
+		c.span.SetAttributes(
+			semconv.CmdStreamConnectionCloseReasonKey.String(string(reason)))
+		if reason == semconv.ConnError {
+			c.span.SetStatus(codes.Error, string(reason))
+		}
+		c.span.End()
+	}
+}
+
+func closeReason(err error) semconv.CmdStreamConnectionCloseReason {
+	if err == nil {
+		return semconv.ServerClosed
+	}
+	if errors.Is(err, io.EOF) {
+		return semconv.ClientClosed
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return semconv.TimedOut
+	}
+	return semconv.ConnError
+}
+
+func addrKey(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	return addr.Network() + "://" + addr.String()
+}
@@ -0,0 +1,180 @@
+package otelcmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"testing"
+
+	cmock "github.com/cmd-stream/cmd-stream-go/test/mock"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"github.com/cmd-stream/otelcmd-stream-go/test/mock"
+	asserterror "github.com/ymz-ncnk/assert/error"
+	"github.com/ymz-ncnk/mok"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestListener(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 9000}
+
+	t.Run("Listener should record connection metrics", func(t *testing.T) {
+		var (
+			reasonSet = attribute.NewSet(
+				semconv.CmdStreamConnectionCloseReasonKey.String(
+					string(semconv.ClientClosed)),
+			)
+			active = mock.NewInt64UpDownCounter().RegisterAdd(
+				func(ctx context.Context, incr int64, options ...metric.AddOption) {
+					asserterror.Equal(t, incr, 1)
+				},
+			).RegisterAdd(
+				func(ctx context.Context, incr int64, options ...metric.AddOption) {
+					asserterror.Equal(t, incr, -1)
+				},
+			)
+			count = mock.NewInt64Counter().RegisterAdd(
+				func(ctx context.Context, incr int64, options ...metric.AddOption) {
+					config := metric.NewAddConfig(options)
+					asserterror.EqualDeep(t, config.Attributes(), reasonSet)
+				},
+			)
+			duration = mock.NewFloat64Histogram().RegisterRecord(
+				func(ctx context.Context, incr float64,
+					options ...metric.RecordOption) {
+					config := metric.NewRecordConfig(options)
+					asserterror.EqualDeep(t, config.Attributes(), reasonSet)
+				},
+			)
+			meter = mock.NewMeter().RegisterInt64UpDownCounter(
+				func(name string, options ...metric.Int64UpDownCounterOption) (
+					metric.Int64UpDownCounter, error) {
+					asserterror.Equal(t, name,
+						semconv.CmdStreamServerConnectionActiveName)
+					return active, nil
+				},
+			).RegisterInt64Counter(
+				func(name string, options ...metric.Int64CounterOption) (
+					metric.Int64Counter, error) {
+					asserterror.Equal(t, name,
+						semconv.CmdStreamServerConnectionCountName)
+					return count, nil
+				},
+			).RegisterFloat64Histogram(
+				func(name string, options ...metric.Float64HistogramOption) (
+					metric.Float64Histogram, error) {
+					asserterror.Equal(t, name,
+						semconv.CmdStreamServerConnectionDurationName)
+					return duration, nil
+				},
+			)
+			meterProvider = mock.NewMeterProvider().RegisterMeter(
+				func(name string, options ...metric.MeterOption) metric.Meter {
+					return meter
+				},
+			)
+			conn = cmock.NewConn().RegisterRead(
+				func(b []byte) (n int, err error) { return 0, io.EOF },
+			).RegisterClose(
+				func() (err error) { return nil },
+			).RegisterClose(
+				func() (err error) { return nil },
+			)
+			listener = cmock.NewListener().RegisterAccept(
+				func() (net.Conn, error) { return conn, nil },
+			)
+			l = NewListener(listener, WithConnMeterProvider(meterProvider))
+		)
+		c, err := l.Accept()
+		asserterror.EqualError(t, err, nil)
+		c.Read(nil)
+		c.Close()
+		c.Close()
+		asserterror.EqualDeep(t, mok.CheckCalls([]*mok.Mock{meter.Mock,
+			active.Mock, count.Mock, duration.Mock, conn.Mock, listener.Mock}),
+			mok.EmptyInfomap)
+	})
+
+	t.Run("Connection span context should be available until close",
+		func(t *testing.T) {
+			var (
+				sc = trace.NewSpanContext(trace.SpanContextConfig{
+					TraceID: trace.TraceID{1},
+					SpanID:  trace.SpanID{1},
+				})
+				span = mock.NewSpan().RegisterSpanContext(
+					func() trace.SpanContext { return sc },
+				).RegisterAddEvent(
+					func(name string, options ...trace.EventOption) {
+						asserterror.Equal(t, name, "server_info")
+					},
+				).RegisterSetAttributes(
+					func(attrs ...attribute.KeyValue) {
+						asserterror.EqualDeep(t, attrs, []attribute.KeyValue{
+							semconv.CmdStreamConnectionCloseReasonKey.String(
+								string(semconv.ServerClosed)),
+						})
+					},
+				).RegisterEnd(
+					func(options ...trace.SpanEndOption) {},
+				)
+				tracer = mock.NewTracer().RegisterStart(
+					func(ctx context.Context, spanName string,
+						opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+						asserterror.Equal(t, spanName, "cmd-stream connection")
+						return ctx, span
+					},
+				)
+				tracerProvider = mock.NewTracerProvider().RegisterTracer(
+					func(name string, options ...trace.TracerOption) trace.Tracer {
+						return tracer
+					},
+				)
+				conn = cmock.NewConn().RegisterRemoteAddr(
+					func() net.Addr { return addr },
+				).RegisterRemoteAddr(
+					func() net.Addr { return addr },
+				).RegisterWrite(
+					func(b []byte) (n int, err error) { return len(b), nil },
+				).RegisterWrite(
+					func(b []byte) (n int, err error) { return len(b), nil },
+				).RegisterRemoteAddr(
+					func() net.Addr { return addr },
+				).RegisterClose(
+					func() (err error) { return nil },
+				)
+				listener = cmock.NewListener().RegisterAccept(
+					func() (net.Conn, error) { return conn, nil },
+				)
+				l = NewListener(listener,
+					WithConnSpan(),
+					WithConnTracerProvider(tracerProvider),
+					WithConnMeterProvider(nil),
+					WithConnSemconvStability(semconv.StabilityOld),
+				)
+			)
+			c, _ := l.Accept()
+			c.Write([]byte("info"))
+			c.Write([]byte("result"))
+
+			got, ok := l.ConnSpanContext(addr)
+			asserterror.Equal(t, ok, true)
+			asserterror.Equal(t, got.Equal(sc), true)
+
+			c.Close()
+			_, ok = l.ConnSpanContext(addr)
+			asserterror.Equal(t, ok, false)
+			asserterror.EqualDeep(t, mok.CheckCalls([]*mok.Mock{span.Mock,
+				tracer.Mock, conn.Mock}), mok.EmptyInfomap)
+		})
+
+	t.Run("closeReason should classify errors", func(t *testing.T) {
+		asserterror.Equal(t, closeReason(nil), semconv.ServerClosed)
+		asserterror.Equal(t, closeReason(io.EOF), semconv.ClientClosed)
+		asserterror.Equal(t, closeReason(os.ErrDeadlineExceeded), semconv.TimedOut)
+		asserterror.Equal(t, closeReason(errors.New("reset")), semconv.ConnError)
+	})
+}
@@ -2,6 +2,7 @@ package otelcmd
 
 import (
 	"net"
+	"time"
 
 	"github.com/cmd-stream/cmd-stream-go/core"
 	internal_semconv "github.com/cmd-stream/otelcmd-stream-go/internal/semconv"
@@ -18,6 +19,10 @@ const ScopeName = "github.com/cmd-stream/otelcmd-stream-go"
 
 type SpanNameFormatterFn[T any] func(cmd core.Cmd[T]) string
 
+// RemoteAddrFn returns the remote address of the client connection, or nil if
+// it is unknown.
+type RemoteAddrFn func() net.Addr
+
 type CmdMetricAttributesFn[T any] func(sentCmd hooks.SentCmd[T],
 	status semconv.CmdStreamCommandStatus,
 	elapsedTime float64,
@@ -34,8 +39,13 @@ type SpanAttributesFn[T any] func(remoteAddr net.Addr,
 type SpanResultEventAttributesFn[T any] func(sentCmd hooks.SentCmd[T],
 	recvResult hooks.ReceivedResult) []attribute.KeyValue
 
+// BaggageFn returns baggage members, as key-value pairs, derived from the
+// Command. They are added to the baggage propagated with the Command.
+type BaggageFn[T any] func(cmd core.Cmd[T]) map[string]string
+
 type Options[T any] struct {
 	ServerAddr        net.Addr
+	RemoteAddrFn      RemoteAddrFn
 	Tracer            trace.Tracer
 	Meter             metric.Meter
 	SpanStartOptions  []trace.SpanStartOption
@@ -47,9 +57,47 @@ type Options[T any] struct {
 
 	SpanAttributesFn            SpanAttributesFn[T]
 	SpanResultEventAttributesFn SpanResultEventAttributesFn[T]
+	ContextAttributesFn         ContextAttributesFn
 
 	CmdMetricAttributesFn    CmdMetricAttributesFn[T]
 	ResultMetricAttributesFn ResultMetricAttributesFn[T]
+
+	BaggageFn               BaggageFn[T]
+	BaggageAttributeKeys    []string
+	BaggageMetricAttributes bool
+	BaggageLimits           BaggageLimits
+
+	DeadlinePropagation bool
+	DeadlineMaxTransit  time.Duration
+	SkipExpired         bool
+
+	PanicRecovery PanicRecoveryMode
+
+	RecordErrorFn      RecordErrorFn
+	ErrorDescriptionFn ErrorDescriptionFn
+
+	AttributePolicy *AttributePolicy
+	attributeFilter *attributeFilter
+
+	CardinalityLimits  map[string]int
+	cardinalityLimiter *internal_semconv.CardinalityLimiter
+
+	RPCSemconv bool
+
+	SemconvStability semconv.Stability
+
+	ClientIndex bool
+
+	ConnSpanContexts ConnSpanContexts
+
+	CodecEvents bool
+
+	ResultSendSpans bool
+
+	MaxSpanLifetime  time.Duration
+	MaxSpanLifetimes map[string]time.Duration
+
+	HooksPool bool
 }
 
 // SpanAttributes returns span attributes for the given peer address and sent
@@ -109,6 +157,15 @@ func WithServerAddr[T any](addr net.Addr) SetOption[T] {
 	}
 }
 
+// WithRemoteAddrFn sets the function that returns the actual remote address
+// of the client connection, see ConnFactory. ServerAddr is used when it returns
+// nil. Client only.
+func WithRemoteAddrFn[T any](fn RemoteAddrFn) SetOption[T] {
+	return func(o *Options[T]) {
+		o.RemoteAddrFn = fn
+	}
+}
+
 // WithPropagator sets the OpenTelemetry TextMapPropagator.
 func WithPropagator[T any](p propagation.TextMapPropagator) SetOption[T] {
 	return func(o *Options[T]) {
@@ -145,39 +202,269 @@ func WithSpanStartOption[T any](s trace.SpanStartOption) SetOption[T] {
 	}
 }
 
-// WithSpanAttributesFn sets the function that returns additional span
+// WithSpanAttributesFn adds a function that returns additional span
 // attributes.
+//
+// Can be used several times, in which case the functions are called in order
+// and their results are merged. For duplicate keys, the last value wins. The
+// same applies to all With*AttributesFn options.
 func WithSpanAttributesFn[T any](fn SpanAttributesFn[T]) SetOption[T] {
 	return func(o *Options[T]) {
-		o.SpanAttributesFn = fn
+		o.SpanAttributesFn = chainSpanAttributesFns(o.SpanAttributesFn, fn)
 	}
 }
 
-// WithSpanResultEventAttributesFn sets the function that returns additional
+// WithSpanResultEventAttributesFn adds a function that returns additional
 // span event attributes.
 func WithSpanResultEventAttributesFn[T any](
 	fn SpanResultEventAttributesFn[T]) SetOption[T] {
 	return func(o *Options[T]) {
-		o.SpanResultEventAttributesFn = fn
+		o.SpanResultEventAttributesFn = chainSpanResultEventAttributesFns(
+			o.SpanResultEventAttributesFn, fn)
+	}
+}
+
+// WithContextAttributesFn adds a function that returns additional span
+// attributes derived from the context, see ContextAttributes.
+func WithContextAttributesFn[T any](fn ContextAttributesFn) SetOption[T] {
+	return func(o *Options[T]) {
+		o.ContextAttributesFn = chainContextAttributesFns(o.ContextAttributesFn,
+			fn)
 	}
 }
 
-// WithCmdMetricAttributesFn sets the function that returns Command metric
+// WithCmdMetricAttributesFn adds a function that returns Command metric
 // attributes.
 func WithCmdMetricAttributesFn[T any](fn CmdMetricAttributesFn[T]) SetOption[T] {
 	return func(o *Options[T]) {
-		o.CmdMetricAttributesFn = fn
+		o.CmdMetricAttributesFn = chainCmdMetricAttributesFns(
+			o.CmdMetricAttributesFn, fn)
 	}
 }
 
-// WithResultMetricAttributesFn sets the function that returns Result metric
+// WithResultMetricAttributesFn adds a function that returns Result metric
 // attributes.
 func WithResultMetricAttributesFn[T any](fn ResultMetricAttributesFn[T]) SetOption[T] {
 	return func(o *Options[T]) {
-		o.ResultMetricAttributesFn = fn
+		o.ResultMetricAttributesFn = chainResultMetricAttributesFns(
+			o.ResultMetricAttributesFn, fn)
+	}
+}
+
+// WithBaggageFn sets the function that returns baggage members derived from
+// the Command. Client only.
+func WithBaggageFn[T any](fn BaggageFn[T]) SetOption[T] {
+	return func(o *Options[T]) {
+		o.BaggageFn = fn
+	}
+}
+
+// WithBaggageAttributes appends baggage keys whose members are copied onto
+// span attributes. Server only.
+func WithBaggageAttributes[T any](keys ...string) SetOption[T] {
+	return func(o *Options[T]) {
+		o.BaggageAttributeKeys = append(o.BaggageAttributeKeys, keys...)
+	}
+}
+
+// WithBaggageMetricAttributes enables copying of the allow-listed baggage
+// members onto metric attributes as well. Server only.
+func WithBaggageMetricAttributes[T any]() SetOption[T] {
+	return func(o *Options[T]) {
+		o.BaggageMetricAttributes = true
+	}
+}
+
+// WithBaggageLimits sets the baggage size limits.
+func WithBaggageLimits[T any](limits BaggageLimits) SetOption[T] {
+	return func(o *Options[T]) {
+		o.BaggageLimits = limits
+	}
+}
+
+// WithDeadlinePropagation enables propagation of the context deadline from
+// the client to the server through the TraceCmd carrier. Should be set on both
+// sides.
+//
+// On the server, the remaining time budget starts counting when the Command
+// is received. Unlike the client's send time, the receive time doesn't depend
+// on the clocks being synchronized, so by default the transit time isn't
+// subtracted from the budget, see WithDeadlineMaxTransit.
+func WithDeadlinePropagation[T any]() SetOption[T] {
+	return func(o *Options[T]) {
+		o.DeadlinePropagation = true
+	}
+}
+
+// WithDeadlineMaxTransit makes the server subtract the transit time, the
+// difference between its clock and the client's send time, from the
+// propagated budget, capped at max. Use it only if the clocks are
+// synchronized. Server only.
+func WithDeadlineMaxTransit[T any](max time.Duration) SetOption[T] {
+	return func(o *Options[T]) {
+		o.DeadlineMaxTransit = max
+	}
+}
+
+// WithSkipExpired makes the server skip Commands whose propagated deadline
+// has already passed. By default, such Commands are executed with the expired
+// context. In both cases they are counted by the
+// cmd-stream.server.command.expired metric, and their spans and Command
+// metrics get the EXPIRED status. Server only.
+func WithSkipExpired[T any]() SetOption[T] {
+	return func(o *Options[T]) {
+		o.SkipExpired = true
+	}
+}
+
+// WithPanicRecovery sets how panics in the wrapped invoker are handled.
+// Server only.
+func WithPanicRecovery[T any](mode PanicRecoveryMode) SetOption[T] {
+	return func(o *Options[T]) {
+		o.PanicRecovery = mode
+	}
+}
+
+// WithRecordErrorFn sets the function that decides which errors are recorded
+// as exception events on spans. By default, no exception events are recorded.
+func WithRecordErrorFn[T any](fn RecordErrorFn) SetOption[T] {
+	return func(o *Options[T]) {
+		o.RecordErrorFn = fn
+	}
+}
+
+// WithErrorDescriptionFn sets the function that produces error descriptions
+// for span statuses and exception events. It can be used to keep sensitive
+// data out of traces, see TypeErrorDescription and ScrubErrorDescription.
+func WithErrorDescriptionFn[T any](fn ErrorDescriptionFn) SetOption[T] {
+	return func(o *Options[T]) {
+		o.ErrorDescriptionFn = fn
+	}
+}
+
+// WithAttributePolicy sets the policy applied to attributes returned by the
+// user-supplied attribute functions.
+func WithAttributePolicy[T any](policy AttributePolicy) SetOption[T] {
+	return func(o *Options[T]) {
+		o.AttributePolicy = &policy
+	}
+}
+
+// WithCardinalityLimit limits the number of distinct values of the metric
+// attribute with the specified key, for example
+// semconv.CmdStreamCommandTypeKey. Once the limit is reached, new values are
+// replaced with "_OTHER" and counted by the
+// "cmd-stream.attribute.cardinality.overflow" metric.
+//
+// Can be used several times to limit several keys.
+func WithCardinalityLimit[T any](key attribute.Key, limit int) SetOption[T] {
+	return func(o *Options[T]) {
+		if o.CardinalityLimits == nil {
+			o.CardinalityLimits = map[string]int{}
+		}
+		o.CardinalityLimits[string(key)] = limit
+	}
+}
+
+// WithRPCSemconv enables the RPC-compat mode. In this mode, the rpc.system,
+// rpc.service and rpc.method attributes are added to spans, and the
+// rpc.client.duration/rpc.server.duration histograms are recorded in seconds.
+//
+// rpc.service is the receiver type name and rpc.method - the Command type.
+// The number of rpc.method values can be limited with WithCardinalityLimit.
+func WithRPCSemconv[T any]() SetOption[T] {
+	return func(o *Options[T]) {
+		o.RPCSemconv = true
+	}
+}
+
+// WithSemconvStability sets which address attributes are emitted, overriding
+// the OTEL_SEMCONV_STABILITY_OPT_IN environment variable.
+func WithSemconvStability[T any](stability semconv.Stability) SetOption[T] {
+	return func(o *Options[T]) {
+		o.SemconvStability = stability
+	}
+}
+
+// WithClientIndex adds the cmd-stream.client.index attribute, the index of the
+// client within the group, to client spans and metrics. Requires
+// DispatchStrategyFactory and TraceCmd Commands. Client only.
+func WithClientIndex[T any]() SetOption[T] {
+	return func(o *Options[T]) {
+		o.ClientIndex = true
+	}
+}
+
+// WithConnSpanLinks links Command spans to the spans of the connections they
+// were received on, see Listener and WithConnSpan. Server only.
+func WithConnSpanLinks[T any](spans ConnSpanContexts) SetOption[T] {
+	return func(o *Options[T]) {
+		o.ConnSpanContexts = spans
+	}
+}
+
+// WithCodecEvents lets the ClientCodec add the encoded event to the spans of
+// TraceCmd Commands created by NewTraceCmd, see WithCodecSpanEvents. Client
+// only.
+func WithCodecEvents[T any]() SetOption[T] {
+	return func(o *Options[T]) {
+		o.CodecEvents = true
 	}
 }
 
+// WithResultSendSpans makes each Result send its own child span of the Command
+// span, "Send result <Type>", with the send duration, the number of bytes
+// written, the deadline and the write error, if any. Server only.
+func WithResultSendSpans[T any]() SetOption[T] {
+	return func(o *Options[T]) {
+		o.ResultSendSpans = true
+	}
+}
+
+// WithMaxSpanLifetime ends the client span with the ABANDONED status if no
+// terminal hook (OnResult with the last Result, OnError or OnTimeout) is called
+// within the specified duration, such Commands are counted by the
+// "cmd-stream.client.command.abandoned" metric. Client only.
+func WithMaxSpanLifetime[T any](d time.Duration) SetOption[T] {
+	return func(o *Options[T]) {
+		o.MaxSpanLifetime = d
+	}
+}
+
+// WithCmdMaxSpanLifetime overrides the maximum span lifetime, see
+// WithMaxSpanLifetime, for the Command type, cmdType is the value of the
+// cmd-stream.command.type attribute.
+//
+// Can be used several times to configure several Command types.
+func WithCmdMaxSpanLifetime[T any](cmdType string, d time.Duration) SetOption[T] {
+	return func(o *Options[T]) {
+		if o.MaxSpanLifetimes == nil {
+			o.MaxSpanLifetimes = map[string]time.Duration{}
+		}
+		o.MaxSpanLifetimes[cmdType] = d
+	}
+}
+
+// WithHooksPool makes HooksFactory reuse Hooks through a sync.Pool. Hooks is
+// returned to the pool only by the call that ends the Command, such as
+// OnError, OnTimeout or the last OnResult. Late or concurrent calls, like
+// OnResult after OnTimeout, are dropped: the context returned by BeforeSend
+// carries the Hooks generation, which changes on release. Client only.
+func WithHooksPool[T any]() SetOption[T] {
+	return func(o *Options[T]) {
+		o.HooksPool = true
+	}
+}
+
+// maxSpanLifetime returns the maximum span lifetime for the Command, 0 means
+// no limit.
+func (o Options[T]) maxSpanLifetime(cmd core.Cmd[T]) time.Duration {
+	if d, ok := o.MaxSpanLifetimes[internal_semconv.TypeStr(cmd)]; ok {
+		return d
+	}
+	return o.MaxSpanLifetime
+}
+
 func Apply[T any](ops []SetOption[T], o *Options[T]) {
 	for i := range ops {
 		if ops[i] != nil {
@@ -193,6 +480,17 @@ func Apply[T any](ops []SetOption[T], o *Options[T]) {
 			metric.WithInstrumentationVersion(Version()),
 		)
 	}
+	if o.AttributePolicy != nil {
+		o.attributeFilter = newAttributeFilter(*o.AttributePolicy, o.Meter)
+	}
+	if len(o.CardinalityLimits) > 0 {
+		limits := make(map[attribute.Key]int, len(o.CardinalityLimits))
+		for key, limit := range o.CardinalityLimits {
+			limits[attribute.Key(key)] = limit
+		}
+		o.cardinalityLimiter = internal_semconv.NewCardinalityLimiter(limits,
+			o.Meter)
+	}
 }
 
 func defaultClientSpanNameFormatter[T any](cmd core.Cmd[T]) string {
@@ -0,0 +1,70 @@
+package otelcmd
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/cmd-stream/cmd-stream-go/sender/hooks"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"go.opentelemetry.io/otel/codes"
+	otel_semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PanicErrorType is the error.type value used for panics recovered in the
+// wrapped invoker.
+const PanicErrorType = "panic"
+
+// PanicRecoveryMode defines how Invoker handles a panic in the wrapped invoker.
+type PanicRecoveryMode int
+
+const (
+	// NoPanicRecovery leaves panics untouched, in this case the span is never
+	// ended and metrics are not recorded.
+	NoPanicRecovery PanicRecoveryMode = iota
+
+	// RecoverAndRepanic records the panic and then panics again with the same
+	// value.
+	RecoverAndRepanic
+
+	// RecoverToError records the panic and returns it from Invoke as a
+	// *PanicError.
+	RecoverToError
+)
+
+// PanicError is returned by Invoker.Invoke when a panic is recovered in the
+// RecoverToError mode.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// recoverPanic should be deferred. It records a panic of the wrapped invoker
+// and then, depending on the PanicRecoveryMode, re-panics or sets err.
+func (i Invoker[T]) recoverPanic(ctx context.Context, span trace.Span,
+	sentCmd hooks.SentCmd[T], startTime time.Time, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	var (
+		perr    = &PanicError{Value: r, Stack: debug.Stack()}
+		errAttr = otel_semconv.ErrorTypeKey.String(PanicErrorType)
+	)
+	span.SetAttributes(errAttr)
+	i.options.recordException(span, perr, perr.Stack)
+	span.SetStatus(codes.Error, i.options.errorDescription(perr))
+	i.recordCmdMetrics(ctx, sentCmd, semconv.Failed, ElapsedTime(startTime),
+		errAttr)
+	span.End()
+	if i.options.PanicRecovery == RecoverAndRepanic {
+		panic(r)
+	}
+	*err = perr
+}
@@ -0,0 +1,108 @@
+package otelcmd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cmd-stream/cmd-stream-go/core"
+	cmock "github.com/cmd-stream/cmd-stream-go/test/mock"
+	"github.com/cmd-stream/otelcmd-stream-go/test/mock"
+	asserterror "github.com/ymz-ncnk/assert/error"
+	"github.com/ymz-ncnk/mok"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric/noop"
+	otel_semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestPanicRecovery(t *testing.T) {
+	t.Run("RecoverToError should record the panic and return an error",
+		func(t *testing.T) {
+			span, mocks := mockPanicSpan(t)
+			invoker := newPanicInvoker(span, RecoverToError)
+
+			err := invoker.Invoke(context.Background(), CmdSeq, time.Now(), CmdSize,
+				cmock.NewCmd[any](), newPanicProxy())
+
+			var perr *PanicError
+			asserterror.Equal(t, errors.As(err, &perr), true)
+			asserterror.Equal(t, perr.Value, any("boom"))
+			asserterror.EqualDeep(t, mok.CheckCalls(mocks), mok.EmptyInfomap)
+		})
+
+	t.Run("RecoverAndRepanic should record the panic and panic again",
+		func(t *testing.T) {
+			span, mocks := mockPanicSpan(t)
+			invoker := newPanicInvoker(span, RecoverAndRepanic)
+
+			defer func() {
+				asserterror.Equal(t, recover(), any("boom"))
+				asserterror.EqualDeep(t, mok.CheckCalls(mocks), mok.EmptyInfomap)
+			}()
+			invoker.Invoke(context.Background(), CmdSeq, time.Now(), CmdSize,
+				cmock.NewCmd[any](), newPanicProxy())
+		})
+}
+
+func newPanicInvoker(span mock.Span, mode PanicRecoveryMode) Invoker[any] {
+	var (
+		tracer = mock.NewTracer().RegisterStart(
+			func(ctx context.Context, spanName string,
+				opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+				return trace.ContextWithSpan(ctx, span), span
+			},
+		)
+		tracerProvider = mock.NewTracerProvider().RegisterTracer(
+			func(name string, options ...trace.TracerOption) trace.Tracer {
+				return tracer
+			},
+		)
+		invoker = cmock.NewInvoker[any]().RegisterInvoke(
+			func(ctx context.Context, seq core.Seq, at time.Time, bytesRead int,
+				cmd core.Cmd[any], proxy core.Proxy) error {
+				panic("boom")
+			},
+		)
+	)
+	return NewInvoker[any](invoker,
+		WithTracerProvider[any](tracerProvider),
+		WithMeterProvider[any](noop.NewMeterProvider()),
+		WithPanicRecovery[any](mode),
+	)
+}
+
+func newPanicProxy() cmock.Proxy {
+	return cmock.NewProxy().RegisterRemoteAddr(
+		func() net.Addr { return &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 8080} },
+	)
+}
+
+func mockPanicSpan(t *testing.T) (span mock.Span, mocks []*mok.Mock) {
+	span = mock.NewSpan().RegisterSetAttributes(
+		func(attrs ...attribute.KeyValue) {},
+	).RegisterSetAttributes(
+		func(attrs ...attribute.KeyValue) {
+			asserterror.EqualDeep(t, attrs, []attribute.KeyValue{
+				otel_semconv.ErrorTypeKey.String(PanicErrorType),
+			})
+		},
+	).RegisterRecordError(
+		func(err error, options ...trace.EventOption) {
+			config := trace.NewEventConfig(options...)
+			asserterror.Equal(t, config.StackTrace(), true)
+		},
+	).RegisterSetStatus(
+		func(code codes.Code, description string) {
+			asserterror.Equal(t, code, codes.Error)
+			asserterror.Equal(t, description, "panic: boom")
+		},
+	).RegisterEnd(
+		func(options ...trace.SpanEndOption) {},
+	)
+	mocks = []*mok.Mock{span.Mock}
+	return
+}
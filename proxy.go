@@ -1,14 +1,30 @@
 package otelcmd
 
 import (
+	"context"
+	"errors"
 	"net"
+	"os"
+	"sync/atomic"
 	"time"
 
 	"github.com/cmd-stream/cmd-stream-go/core"
 	"github.com/cmd-stream/cmd-stream-go/sender/hooks"
+	internal_semconv "github.com/cmd-stream/otelcmd-stream-go/internal/semconv"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
-type ProxyCallbackFn func(recvResult hooks.ReceivedResult)
+// ProxyCallbackFn is called when the Result is sent, recvResult.Seq is the
+// sequence number of the Result among the Results of the Command, starting
+// from 1, like on the client, cmdSeq is the sequence number of the Command
+// sent over the wire.
+type ProxyCallbackFn func(recvResult hooks.ReceivedResult, cmdSeq core.Seq)
+
+// ProxyErrorCallbackFn is called when the Result fails to be sent,
+// deadlineExceeded reports whether the write deadline was exceeded.
+type ProxyErrorCallbackFn func(result core.Result, err error,
+	deadlineExceeded bool)
 
 func NewProxy[T any](proxy core.Proxy, callback ProxyCallbackFn) *Proxy[T] {
 	return &Proxy[T]{proxy: proxy, callback: callback}
@@ -17,7 +33,9 @@ func NewProxy[T any](proxy core.Proxy, callback ProxyCallbackFn) *Proxy[T] {
 type Proxy[T any] struct {
 	proxy     core.Proxy
 	callback  ProxyCallbackFn
-	resultSeq core.Seq
+	resultSeq atomic.Int64
+	sendSpans *resultSendSpans[T]
+	onError   ProxyErrorCallbackFn
 }
 
 func (p *Proxy[T]) LocalAddr() net.Addr {
@@ -29,23 +47,97 @@ func (p *Proxy[T]) RemoteAddr() net.Addr {
 }
 
 func (p *Proxy[T]) Send(seq core.Seq, result core.Result) (n int, err error) {
+	span, startTime := p.startSendSpan(result)
 	n, err = p.proxy.Send(seq, result)
 	if err != nil {
+		p.endSendSpan(span, startTime, time.Time{}, seq, 0, result, n, err)
+		p.sendFailed(result, err)
 		return
 	}
-	p.resultSeq += 1
-	p.callback(hooks.ReceivedResult{Seq: p.resultSeq, Size: n, Result: result})
+	resultSeq := core.Seq(p.resultSeq.Add(1))
+	p.endSendSpan(span, startTime, time.Time{}, seq, resultSeq, result, n, nil)
+	p.callback(hooks.ReceivedResult{Seq: resultSeq, Size: n, Result: result},
+		seq)
 	return
 }
 
 func (p *Proxy[T]) SendWithDeadline(deadline time.Time, seq core.Seq,
 	result core.Result,
 ) (n int, err error) {
+	span, startTime := p.startSendSpan(result)
 	n, err = p.proxy.SendWithDeadline(deadline, seq, result)
 	if err != nil {
+		p.endSendSpan(span, startTime, deadline, seq, 0, result, n, err)
+		p.sendFailed(result, err)
+		return
+	}
+	resultSeq := core.Seq(p.resultSeq.Add(1))
+	p.endSendSpan(span, startTime, deadline, seq, resultSeq, result, n, nil)
+	p.callback(hooks.ReceivedResult{Seq: resultSeq, Size: n, Result: result},
+		seq)
+	return
+}
+
+// WithErrorCallback sets the callback called on failed sends.
+func (p *Proxy[T]) WithErrorCallback(callback ProxyErrorCallbackFn) *Proxy[T] {
+	p.onError = callback
+	return p
+}
+
+func (p *Proxy[T]) sendFailed(result core.Result, err error) {
+	if p.onError != nil {
+		p.onError(result, err, isDeadlineExceeded(err))
+	}
+}
+
+// withSendSpans makes each send a child span of the span in ctx, see
+// WithResultSendSpans.
+func (p *Proxy[T]) withSendSpans(ctx context.Context,
+	options Options[T]) *Proxy[T] {
+	p.sendSpans = &resultSendSpans[T]{ctx: ctx, options: options}
+	return p
+}
+
+func (p *Proxy[T]) startSendSpan(result core.Result) (span trace.Span,
+	startTime time.Time) {
+	if p.sendSpans == nil {
 		return
 	}
-	p.resultSeq += 1
-	p.callback(hooks.ReceivedResult{Seq: p.resultSeq, Size: n, Result: result})
+	startTime = time.Now()
+	_, span = p.sendSpans.options.Tracer.Start(p.sendSpans.ctx,
+		internal_semconv.ResultSendSpanName(result),
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithTimestamp(startTime),
+	)
 	return
 }
+
+// endSendSpan ends the send span, resultSeq is 0 if the Result was not sent.
+func (p *Proxy[T]) endSendSpan(span trace.Span, startTime, deadline time.Time,
+	cmdSeq, resultSeq core.Seq, result core.Result, n int, err error) {
+	if span == nil {
+		return
+	}
+	span.SetAttributes(internal_semconv.ResultSendSpanAttrs(cmdSeq,
+		resultSeq, result, n, time.Since(startTime).Seconds(), deadline)...)
+	if err != nil {
+		span.SetAttributes(internal_semconv.ErrAttrs(err)...)
+		p.sendSpans.options.recordError(span, err)
+		span.SetStatus(codes.Error, p.sendSpans.options.errorDescription(err))
+	}
+	span.End()
+}
+
+type resultSendSpans[T any] struct {
+	ctx     context.Context
+	options Options[T]
+}
+
+func isDeadlineExceeded(err error) bool {
+	if errors.Is(err, os.ErrDeadlineExceeded) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,212 @@
+package otelcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cmd-stream/cmd-stream-go/core"
+	"github.com/cmd-stream/cmd-stream-go/sender/hooks"
+	cmock "github.com/cmd-stream/cmd-stream-go/test/mock"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"github.com/cmd-stream/otelcmd-stream-go/test/mock"
+	asserterror "github.com/ymz-ncnk/assert/error"
+	"github.com/ymz-ncnk/mok"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	otel_semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestProxy(t *testing.T) {
+	t.Run("With send spans, Send should start and end a child span",
+		func(t *testing.T) {
+			var (
+				result   = cmock.NewResult()
+				wantSize = 10
+				attrs    []attribute.KeyValue
+				span     = mock.NewSpan().RegisterSetAttributes(
+					func(kv ...attribute.KeyValue) { attrs = kv },
+				).RegisterEnd(
+					func(options ...trace.SpanEndOption) {},
+				)
+				tracer = mock.NewTracer().RegisterStart(
+					func(ctx context.Context, spanName string,
+						opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+						asserterror.Equal(t, spanName, "Send result Result")
+						return ctx, span
+					},
+				)
+				proxy = cmock.NewProxy().RegisterSend(
+					func(seq core.Seq, r core.Result) (n int, err error) {
+						return wantSize, nil
+					},
+				)
+				callbackCalled = false
+				p              = NewProxy[any](proxy,
+					func(recvResult hooks.ReceivedResult, cmdSeq core.Seq) { callbackCalled = true },
+				).withSendSpans(context.Background(), Options[any]{Tracer: tracer})
+				mocks = []*mok.Mock{span.Mock, tracer.Mock, proxy.Mock}
+			)
+			n, err := p.Send(1, result)
+			asserterror.EqualError(t, err, nil)
+			asserterror.Equal(t, n, wantSize)
+			asserterror.Equal(t, callbackCalled, true)
+			set := attribute.NewSet(attrs...)
+			v, _ := set.Value(semconv.CmdStreamResultSizeKey)
+			asserterror.Equal(t, v.AsInt64(), int64(wantSize))
+			v, _ = set.Value(semconv.CmdStreamCommandSeqKey)
+			asserterror.Equal(t, v.AsInt64(), int64(1))
+			v, _ = set.Value(semconv.CmdStreamResultSeqKey)
+			asserterror.Equal(t, v.AsInt64(), int64(1))
+			_, ok := set.Value(semconv.CmdStreamResultSendDeadlineKey)
+			asserterror.Equal(t, ok, false)
+			asserterror.EqualDeep(t, mok.CheckCalls(mocks), mok.EmptyInfomap)
+		})
+
+	t.Run("Failed SendWithDeadline should set the error status",
+		func(t *testing.T) {
+			var (
+				result   = cmock.NewResult()
+				deadline = time.Now().Add(time.Second)
+				wantErr  = errors.New("write error")
+				attrs    []attribute.KeyValue
+				span     = mock.NewSpan().RegisterSetAttributes(
+					func(kv ...attribute.KeyValue) { attrs = append(attrs, kv...) },
+				).RegisterSetAttributes(
+					func(kv ...attribute.KeyValue) { attrs = append(attrs, kv...) },
+				).RegisterSetStatus(
+					func(code codes.Code, description string) {
+						asserterror.Equal(t, code, codes.Error)
+						asserterror.Equal(t, description, wantErr.Error())
+					},
+				).RegisterEnd(
+					func(options ...trace.SpanEndOption) {},
+				)
+				tracer = mock.NewTracer().RegisterStart(
+					func(ctx context.Context, spanName string,
+						opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+						return ctx, span
+					},
+				)
+				proxy = deadlineProxy{
+					Proxy: cmock.NewProxy(),
+					fn: func(d time.Time, seq core.Seq, r core.Result) (n int,
+						err error) {
+						asserterror.Equal(t, d, deadline)
+						return 0, wantErr
+					},
+				}
+				p = NewProxy[any](proxy,
+					func(recvResult hooks.ReceivedResult, cmdSeq core.Seq) {
+						t.Error("unexpected callback call")
+					},
+				).withSendSpans(context.Background(), Options[any]{Tracer: tracer})
+				mocks = []*mok.Mock{span.Mock, tracer.Mock, proxy.Mock}
+			)
+			_, err := p.SendWithDeadline(deadline, 1, result)
+			asserterror.EqualError(t, err, wantErr)
+			set := attribute.NewSet(attrs...)
+			v, _ := set.Value(semconv.CmdStreamResultSendDeadlineKey)
+			asserterror.Equal(t, v.AsString(),
+				deadline.UTC().Format(time.RFC3339Nano))
+			v, _ = set.Value(otel_semconv.ErrorTypeKey)
+			asserterror.Equal(t, v.AsString(), "*errors.errorString")
+			_, ok := set.Value(semconv.CmdStreamResultSeqKey)
+			asserterror.Equal(t, ok, false)
+			asserterror.EqualDeep(t, mok.CheckCalls(mocks), mok.EmptyInfomap)
+		})
+
+	t.Run("Failed send should call the error callback", func(t *testing.T) {
+		var (
+			wantErr = fmt.Errorf("write: %w", os.ErrDeadlineExceeded)
+			proxy   = deadlineProxy{
+				Proxy: cmock.NewProxy(),
+				fn: func(d time.Time, seq core.Seq, r core.Result) (n int,
+					err error) {
+					return 0, wantErr
+				},
+			}
+			called = false
+			p      = NewProxy[any](proxy,
+				func(recvResult hooks.ReceivedResult, cmdSeq core.Seq) {
+					t.Error("unexpected callback call")
+				},
+			).WithErrorCallback(
+				func(result core.Result, err error, deadlineExceeded bool) {
+					called = true
+					asserterror.EqualError(t, err, wantErr)
+					asserterror.Equal(t, deadlineExceeded, true)
+				},
+			)
+		)
+		_, err := p.SendWithDeadline(time.Now(), 1, cmock.NewResult())
+		asserterror.EqualError(t, err, wantErr)
+		asserterror.Equal(t, called, true)
+	})
+
+	t.Run("Results sent concurrently should get the Command seq and unique Result seqs",
+		func(t *testing.T) {
+			var (
+				wantCmdSeq core.Seq = 7
+				count               = 10
+				proxy               = cmock.NewProxy()
+			)
+			for range count {
+				proxy.RegisterSend(
+					func(seq core.Seq, r core.Result) (n int, err error) {
+						return 1, nil
+					},
+				)
+			}
+			var (
+				mu   sync.Mutex
+				seqs = map[core.Seq]struct{}{}
+				p    = NewProxy[any](proxy,
+					func(recvResult hooks.ReceivedResult, cmdSeq core.Seq) {
+						asserterror.Equal(t, cmdSeq, wantCmdSeq)
+						mu.Lock()
+						seqs[recvResult.Seq] = struct{}{}
+						mu.Unlock()
+					},
+				)
+				wg sync.WaitGroup
+			)
+			for range count {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					p.Send(wantCmdSeq, cmock.NewResult())
+				}()
+			}
+			wg.Wait()
+			asserterror.Equal(t, len(seqs), count)
+			for i := 1; i <= count; i++ {
+				if _, ok := seqs[core.Seq(i)]; !ok {
+					t.Errorf("result seq %d is missing", i)
+				}
+			}
+		})
+
+	t.Run("isDeadlineExceeded", func(t *testing.T) {
+		asserterror.Equal(t, isDeadlineExceeded(os.ErrDeadlineExceeded), true)
+		asserterror.Equal(t, isDeadlineExceeded(context.DeadlineExceeded), true)
+		asserterror.Equal(t, isDeadlineExceeded(errors.New("closed")), false)
+	})
+}
+
+// deadlineProxy overrides SendWithDeadline, the signature of
+// cmock.ProxySendWithDeadlineFn doesn't allow to return the number of bytes.
+type deadlineProxy struct {
+	cmock.Proxy
+	fn func(deadline time.Time, seq core.Seq, result core.Result) (int, error)
+}
+
+func (p deadlineProxy) SendWithDeadline(deadline time.Time, seq core.Seq,
+	result core.Result) (int, error) {
+	return p.fn(deadline, seq, result)
+}
@@ -0,0 +1,227 @@
+package otelcmd
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/cmd-stream/cmd-stream-go/core"
+	internal_semconv "github.com/cmd-stream/otelcmd-stream-go/internal/semconv"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// retryLinksKey is the carrier key used to propagate span contexts of the
+// previous attempts to the server.
+const retryLinksKey = "cmd-stream-retry-links"
+
+// maxRetryLinks is the maximum number of links to the previous attempts. The
+// client span and the carrier get links to the most recent attempts, the
+// server takes only the first maxRetryLinks from the carrier.
+const maxRetryLinks = 32
+
+// Sender sends a Command and waits for the Result, it is implemented by the
+// sender.Sender.
+type Sender[T any] interface {
+	Send(ctx context.Context, cmd core.Cmd[T]) (core.Result, error)
+}
+
+// RetryPolicy configures Retrier.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int
+	// Backoff returns the delay before the attempt, may be nil.
+	Backoff BackoffFn
+	// RetryIf reports whether the failed attempt should be retried, if nil,
+	// all errors are retried.
+	RetryIf func(err error) bool
+}
+
+// NewRetrier creates a new Retrier.
+func NewRetrier[T any](sender Sender[T], policy RetryPolicy,
+	ops ...SetOption[T]) Retrier[T] {
+	o := Options[T]{
+		SpanNameFormatter: defaultClientSpanNameFormatter[T],
+		TracerProvider:    otel.GetTracerProvider(),
+		MeterProvider:     otel.GetMeterProvider(),
+	}
+	Apply(ops, &o)
+	return Retrier[T]{
+		sender:  sender,
+		policy:  policy,
+		semconv: internal_semconv.NewCmdStreamRetry[T](o.Meter),
+		options: o,
+	}
+}
+
+// Retrier sends a Command, retrying failed attempts, within a single logical
+// operation span. Each attempt is recorded by the Hooks as a child span with
+// the cmd-stream.retry.attempt attribute and links to the previous attempts.
+// For TraceCmd Commands these links are also propagated to the server span.
+// Retries are counted by the cmd-stream.client.command.retries metric.
+type Retrier[T any] struct {
+	sender  Sender[T]
+	policy  RetryPolicy
+	semconv internal_semconv.CmdStreamRetry[T]
+	options Options[T]
+}
+
+// Send sends the Command created by newCmd. newCmd is called for each attempt,
+// because a TraceCmd can't be sent twice.
+func (r Retrier[T]) Send(ctx context.Context, newCmd func() core.Cmd[T]) (
+	result core.Result, err error) {
+	var (
+		cmd   = newCmd()
+		state = &retryState{}
+		span  trace.Span
+	)
+	ctx, span = r.options.Tracer.Start(ctx, "Retry "+r.options.spanName(cmd),
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(semconv.CmdStreamCommandTypeKey.String(
+			internal_semconv.TypeStr(cmd))),
+	)
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			if err = r.wait(ctx, attempt); err != nil {
+				break
+			}
+			r.semconv.RecordRetry(ctx, cmd)
+			cmd = newCmd()
+		}
+		state.attempt = attempt
+		result, err = r.sender.Send(context.WithValue(ctx, retryStateKey{}, state),
+			cmd)
+		if err == nil || !r.retry(attempt, err) || ctx.Err() != nil {
+			break
+		}
+	}
+	span.SetAttributes(semconv.CmdStreamRetryAttemptKey.Int(state.attempt))
+	if err != nil {
+		r.options.recordError(span, err)
+		span.SetStatus(codes.Error, r.options.errorDescription(err))
+	}
+	span.End()
+	return
+}
+
+func (r Retrier[T]) retry(attempt int, err error) bool {
+	if attempt >= r.policy.MaxAttempts {
+		return false
+	}
+	return r.policy.RetryIf == nil || r.policy.RetryIf(err)
+}
+
+func (r Retrier[T]) wait(ctx context.Context, attempt int) error {
+	if r.policy.Backoff == nil {
+		return nil
+	}
+	delay := r.policy.Backoff(attempt)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type retryStateKey struct{}
+
+// retryState is shared by the attempts of one operation, which are sent one
+// after another. attempt is the number of the last attempt that was sent.
+type retryState struct {
+	attempt      int
+	spanContexts []trace.SpanContext
+}
+
+func retryFromContext(ctx context.Context) *retryState {
+	state, _ := ctx.Value(retryStateKey{}).(*retryState)
+	return state
+}
+
+// retryLinks returns links to the last maxRetryLinks attempts.
+func retryLinks(spanContexts []trace.SpanContext) []trace.Link {
+	if l := len(spanContexts); l > maxRetryLinks {
+		spanContexts = spanContexts[l-maxRetryLinks:]
+	}
+	links := make([]trace.Link, len(spanContexts))
+	for i := range spanContexts {
+		links[i] = trace.Link{SpanContext: spanContexts[i]}
+	}
+	return links
+}
+
+// injectRetryLinks writes the last maxRetryLinks span contexts into the carrier
+// as "traceID-spanID-traceFlags" triples separated by commas.
+func injectRetryLinks(carrier map[string]string,
+	spanContexts []trace.SpanContext) {
+	if l := len(spanContexts); l > maxRetryLinks {
+		spanContexts = spanContexts[l-maxRetryLinks:]
+	}
+	var b strings.Builder
+	for _, sc := range spanContexts {
+		if !sc.IsValid() {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(sc.TraceID().String())
+		b.WriteByte('-')
+		b.WriteString(sc.SpanID().String())
+		b.WriteByte('-')
+		b.WriteString(sc.TraceFlags().String())
+	}
+	if b.Len() > 0 {
+		carrier[retryLinksKey] = b.String()
+	}
+}
+
+// extractRetryLinks returns links to the previous attempts from the carrier,
+// at most maxRetryLinks.
+func extractRetryLinks(carrier map[string]string) (links []trace.Link) {
+	value, ok := carrier[retryLinksKey]
+	if !ok {
+		return
+	}
+	var pair string
+	for value != "" && len(links) < maxRetryLinks {
+		pair, value, _ = strings.Cut(value, ",")
+		traceIDStr, rest, ok := strings.Cut(pair, "-")
+		if !ok {
+			continue
+		}
+		spanIDStr, flagsStr, ok := strings.Cut(rest, "-")
+		if !ok {
+			continue
+		}
+		flags, err := hex.DecodeString(flagsStr)
+		if err != nil || len(flags) != 1 {
+			continue
+		}
+		traceID, err := trace.TraceIDFromHex(traceIDStr)
+		if err != nil {
+			continue
+		}
+		spanID, err := trace.SpanIDFromHex(spanIDStr)
+		if err != nil {
+			continue
+		}
+		links = append(links, trace.Link{
+			SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID:    traceID,
+				SpanID:     spanID,
+				TraceFlags: trace.TraceFlags(flags[0]),
+				Remote:     true,
+			}),
+		})
+	}
+	return
+}
@@ -0,0 +1,215 @@
+package otelcmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cmd-stream/cmd-stream-go/core"
+	cmock "github.com/cmd-stream/cmd-stream-go/test/mock"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"github.com/cmd-stream/otelcmd-stream-go/test/mock"
+	asserterror "github.com/ymz-ncnk/assert/error"
+	"github.com/ymz-ncnk/mok"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestRetrier(t *testing.T) {
+	t.Run("Failed attempts should be retried until success", func(t *testing.T) {
+		var (
+			wantErr  = errors.New("send error")
+			attempts = []int{}
+			sender   = senderFn[any](func(ctx context.Context,
+				cmd core.Cmd[any]) (core.Result, error) {
+				state := retryFromContext(ctx)
+				if state == nil {
+					t.Fatal("retry state is missing")
+				}
+				attempts = append(attempts, state.attempt)
+				if state.attempt < 3 {
+					return nil, wantErr
+				}
+				return cmock.NewResult(), nil
+			})
+			retrier = NewRetrier[any](sender, RetryPolicy{MaxAttempts: 5},
+				WithTracerProvider[any](tracenoop.NewTracerProvider()),
+				WithMeterProvider[any](noop.NewMeterProvider()),
+			)
+		)
+		_, err := retrier.Send(context.Background(), func() core.Cmd[any] {
+			return cmock.NewCmd[any]()
+		})
+		asserterror.EqualError(t, err, nil)
+		asserterror.EqualDeep(t, attempts, []int{1, 2, 3})
+	})
+
+	t.Run("Retrier should stop after MaxAttempts", func(t *testing.T) {
+		var (
+			wantErr = errors.New("send error")
+			calls   = 0
+			sender  = senderFn[any](func(ctx context.Context,
+				cmd core.Cmd[any]) (core.Result, error) {
+				calls++
+				return nil, wantErr
+			})
+			retrier = NewRetrier[any](sender, RetryPolicy{MaxAttempts: 2},
+				WithTracerProvider[any](tracenoop.NewTracerProvider()),
+				WithMeterProvider[any](noop.NewMeterProvider()),
+			)
+		)
+		_, err := retrier.Send(context.Background(), func() core.Cmd[any] {
+			return cmock.NewCmd[any]()
+		})
+		asserterror.EqualError(t, err, wantErr)
+		asserterror.Equal(t, calls, 2)
+	})
+
+	t.Run("Retrier should not retry if RetryIf returns false",
+		func(t *testing.T) {
+			var (
+				wantErr = errors.New("send error")
+				calls   = 0
+				sender  = senderFn[any](func(ctx context.Context,
+					cmd core.Cmd[any]) (core.Result, error) {
+					calls++
+					return nil, wantErr
+				})
+				retrier = NewRetrier[any](sender, RetryPolicy{
+					MaxAttempts: 3,
+					RetryIf:     func(err error) bool { return false },
+				},
+					WithTracerProvider[any](tracenoop.NewTracerProvider()),
+					WithMeterProvider[any](noop.NewMeterProvider()),
+				)
+			)
+			_, err := retrier.Send(context.Background(), func() core.Cmd[any] {
+				return cmock.NewCmd[any]()
+			})
+			asserterror.EqualError(t, err, wantErr)
+			asserterror.Equal(t, calls, 1)
+		})
+
+	t.Run("Retry links should be injected and extracted", func(t *testing.T) {
+		var (
+			sc1 = trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID: trace.TraceID{1},
+				SpanID:  trace.SpanID{1},
+			})
+			sc2 = trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID: trace.TraceID{1},
+				SpanID:  trace.SpanID{2},
+			})
+			carrier = map[string]string{}
+		)
+		injectRetryLinks(carrier, []trace.SpanContext{sc1, {}, sc2})
+		links := extractRetryLinks(carrier)
+		asserterror.Equal(t, len(links), 2)
+		asserterror.Equal(t, links[0].SpanContext.SpanID(), sc1.SpanID())
+		asserterror.Equal(t, links[1].SpanContext.SpanID(), sc2.SpanID())
+		asserterror.Equal(t, links[1].SpanContext.IsRemote(), true)
+		asserterror.Equal(t, links[1].SpanContext.IsSampled(), false)
+	})
+
+	t.Run("Retry links should keep the trace flags", func(t *testing.T) {
+		var (
+			sc = trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID:    trace.TraceID{1},
+				SpanID:     trace.SpanID{1},
+				TraceFlags: trace.FlagsSampled,
+			})
+			carrier = map[string]string{}
+		)
+		injectRetryLinks(carrier, []trace.SpanContext{{}, sc})
+		asserterror.Equal(t, carrier[retryLinksKey],
+			sc.TraceID().String()+"-"+sc.SpanID().String()+"-01")
+		links := extractRetryLinks(carrier)
+		asserterror.Equal(t, len(links), 1)
+		asserterror.Equal(t, links[0].SpanContext.Equal(sc.WithRemote(true)), true)
+		asserterror.Equal(t, links[0].SpanContext.IsSampled(), true)
+	})
+
+	t.Run("Aborted backoff wait should not be counted as an attempt",
+		func(t *testing.T) {
+			var (
+				ctx, cancel = context.WithCancel(context.Background())
+				span        = mock.NewSpan().RegisterSetAttributes(
+					func(kv ...attribute.KeyValue) {
+						asserterror.EqualDeep(t, kv, []attribute.KeyValue{
+							semconv.CmdStreamRetryAttemptKey.Int(1)})
+					},
+				).RegisterSetStatus(
+					func(code codes.Code, description string) {},
+				).RegisterEnd(
+					func(options ...trace.SpanEndOption) {},
+				)
+				tracer = mock.NewTracer().RegisterStart(
+					func(ctx context.Context, spanName string,
+						opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+						return ctx, span
+					},
+				)
+				tracerProvider = mock.NewTracerProvider().RegisterTracer(
+					func(name string, options ...trace.TracerOption) trace.Tracer {
+						return tracer
+					},
+				)
+				sender = senderFn[any](func(ctx context.Context,
+					cmd core.Cmd[any]) (core.Result, error) {
+					return nil, errors.New("send error")
+				})
+				retrier = NewRetrier[any](sender, RetryPolicy{
+					MaxAttempts: 3,
+					Backoff: func(attempt int) time.Duration {
+						cancel()
+						return time.Hour
+					},
+				},
+					WithTracerProvider[any](tracerProvider),
+					WithMeterProvider[any](noop.NewMeterProvider()),
+				)
+			)
+			_, err := retrier.Send(ctx, func() core.Cmd[any] {
+				return cmock.NewCmd[any]()
+			})
+			asserterror.EqualError(t, err, context.Canceled)
+			asserterror.EqualDeep(t, mok.CheckCalls([]*mok.Mock{span.Mock,
+				tracer.Mock, tracerProvider.Mock}), mok.EmptyInfomap)
+		})
+
+	t.Run("Retry links should be capped on both sides", func(t *testing.T) {
+		var (
+			spanContexts = make([]trace.SpanContext, maxRetryLinks+2)
+			carrier      = map[string]string{}
+		)
+		for i := range spanContexts {
+			spanContexts[i] = trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID: trace.TraceID{1},
+				SpanID:  trace.SpanID{byte(i + 1)},
+			})
+		}
+		links := retryLinks(spanContexts)
+		asserterror.Equal(t, len(links), maxRetryLinks)
+		asserterror.Equal(t, links[0].SpanContext.SpanID(), spanContexts[2].SpanID())
+
+		injectRetryLinks(carrier, spanContexts)
+		links = extractRetryLinks(carrier)
+		asserterror.Equal(t, len(links), maxRetryLinks)
+		asserterror.Equal(t, links[0].SpanContext.SpanID(), spanContexts[2].SpanID())
+
+		carrier[retryLinksKey] += "," + carrier[retryLinksKey]
+		asserterror.Equal(t, len(extractRetryLinks(carrier)), maxRetryLinks)
+	})
+}
+
+type senderFn[T any] func(ctx context.Context, cmd core.Cmd[T]) (core.Result,
+	error)
+
+func (fn senderFn[T]) Send(ctx context.Context, cmd core.Cmd[T]) (core.Result,
+	error) {
+	return fn(ctx, cmd)
+}
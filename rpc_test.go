@@ -0,0 +1,93 @@
+package otelcmd
+
+import (
+	"context"
+	"testing"
+
+	cmock "github.com/cmd-stream/cmd-stream-go/test/mock"
+	internal_semconv "github.com/cmd-stream/otelcmd-stream-go/internal/semconv"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	"github.com/cmd-stream/otelcmd-stream-go/test/mock"
+	asserterror "github.com/ymz-ncnk/assert/error"
+	"github.com/ymz-ncnk/mok"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	otel_semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+)
+
+type rpcReceiver struct{}
+
+func TestRPCSemconv(t *testing.T) {
+	var (
+		cmd       = NewTraceCmd[rpcReceiver](cmock.NewCmd[rpcReceiver]())
+		wantAttrs = []attribute.KeyValue{
+			otel_semconv.RPCSystemKey.String("cmd-stream"),
+			otel_semconv.RPCService("rpcReceiver"),
+			otel_semconv.RPCMethod(internal_semconv.TypeStr(cmd.Cmd)),
+		}
+	)
+
+	t.Run("Invoker should record rpc.server.duration in seconds",
+		func(t *testing.T) {
+			var (
+				histogram = mock.NewFloat64Histogram().RegisterRecord(
+					func(ctx context.Context, incr float64,
+						options ...metric.RecordOption) {
+						config := metric.NewRecordConfig(options)
+						asserterror.Equal(t, incr, 1.5)
+						asserterror.EqualDeep(t, config.Attributes(), attribute.NewSet(
+							append(wantAttrs,
+								semconv.CmdStreamCommandStatusKey.String("OK"))...,
+						))
+					},
+				)
+				meter = mock.NewMeter().RegisterFloat64Histogram(
+					func(name string, options ...metric.Float64HistogramOption) (
+						metric.Float64Histogram, error) {
+						config := metric.NewFloat64HistogramConfig(options...)
+						asserterror.Equal(t, name, otel_semconv.RPCServerDurationName)
+						asserterror.Equal(t, config.Unit(), "s")
+						return histogram, nil
+					},
+				)
+				rpc = internal_semconv.NewRPCServer[rpcReceiver](meter, nil)
+			)
+			asserterror.EqualDeep(t, rpc.Attrs(unwrapCmd[rpcReceiver](cmd)),
+				wantAttrs)
+			rpc.RecordDuration(context.Background(), unwrapCmd[rpcReceiver](cmd),
+				semconv.Ok, 1500)
+			asserterror.EqualDeep(t, mok.CheckCalls([]*mok.Mock{meter.Mock,
+				histogram.Mock}), mok.EmptyInfomap)
+		})
+
+	t.Run("rpc.method should be limited by the cardinality limiter",
+		func(t *testing.T) {
+			var (
+				histogram = mock.NewFloat64Histogram().RegisterRecord(
+					func(ctx context.Context, incr float64,
+						options ...metric.RecordOption) {
+						var (
+							config    = metric.NewRecordConfig(options)
+							attrs     = config.Attributes()
+							method, _ = attrs.Value(otel_semconv.RPCMethodKey)
+						)
+						asserterror.Equal(t, method.AsString(),
+							internal_semconv.OtherValue)
+					},
+				)
+				meter = mock.NewMeter().RegisterFloat64Histogram(
+					func(name string, options ...metric.Float64HistogramOption) (
+						metric.Float64Histogram, error) {
+						return histogram, nil
+					},
+				)
+				limiter = internal_semconv.NewCardinalityLimiter(
+					map[attribute.Key]int{otel_semconv.RPCMethodKey: 0}, nil)
+				rpc = internal_semconv.NewRPCClient[rpcReceiver](meter, limiter)
+			)
+			rpc.RecordDuration(context.Background(), unwrapCmd[rpcReceiver](cmd),
+				semconv.Ok, 1500)
+			asserterror.EqualDeep(t, mok.CheckCalls([]*mok.Mock{meter.Mock,
+				histogram.Mock}), mok.EmptyInfomap)
+		})
+}
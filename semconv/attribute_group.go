@@ -82,3 +82,165 @@ const (
 	// Examples: 256, 8192, 2097152
 	CmdStreamResultSizeKey = attribute.Key("cmd-stream.result.size")
 )
+
+const (
+	// CmdStreamAttributeKeyKey is the attribute Key conforming to the
+	// "cmd-stream.attribute.key" semantic conventions. It represents the key of
+	// a metric attribute whose values exceeded the cardinality limit.
+	//
+	// Type: string
+	// RequirementLevel: Recommended
+	// Stability: Experimental
+	//
+	// Examples: "rpc.method", "tenant.id"
+	CmdStreamAttributeKeyKey = attribute.Key("cmd-stream.attribute.key")
+
+	// CmdStreamAttributeViolationKey is the attribute Key conforming to the
+	// "cmd-stream.attribute.violation" semantic conventions. It represents the
+	// kind of the attribute policy violation.
+	//
+	// Type: string (enum)
+	// RequirementLevel: Recommended
+	// Stability: Experimental
+	//
+	// Examples: "DENIED", "NOT_ALLOWED", "TRUNCATED"
+	CmdStreamAttributeViolationKey = attribute.Key("cmd-stream.attribute.violation")
+)
+
+const (
+	// CmdStreamClientIndexKey is the attribute Key conforming to the
+	// "cmd-stream.client.index" semantic conventions. It represents the index
+	// of the client within the group the Command was sent through.
+	//
+	// Type: int
+	// RequirementLevel: Optional
+	// Stability: Experimental
+	//
+	// Examples: 0, 1, 7
+	CmdStreamClientIndexKey = attribute.Key("cmd-stream.client.index")
+)
+
+const (
+	// CmdStreamConnectionCloseReasonKey is the attribute Key conforming to the
+	// "cmd-stream.connection.close_reason" semantic conventions. It represents
+	// the reason the connection was closed.
+	//
+	// Type: string (enum)
+	// RequirementLevel: Recommended
+	// Stability: Experimental
+	//
+	// Examples: "CLIENT_CLOSED", "SERVER_CLOSED", "TIMEOUT", "ERROR"
+	CmdStreamConnectionCloseReasonKey = attribute.Key("cmd-stream.connection.close_reason")
+)
+
+const (
+	// CmdStreamReconnectAttemptKey is the attribute Key conforming to the
+	// "cmd-stream.reconnect.attempt" semantic conventions. It represents the
+	// number of the reconnect attempt, starting from 1.
+	//
+	// Type: int
+	// RequirementLevel: Recommended
+	// Stability: Experimental
+	//
+	// Examples: 1, 2, 5
+	CmdStreamReconnectAttemptKey = attribute.Key("cmd-stream.reconnect.attempt")
+)
+
+const (
+	// CmdStreamCodecOperationKey is the attribute Key conforming to the
+	// "cmd-stream.codec.operation" semantic conventions. It represents the
+	// codec operation.
+	//
+	// Type: string (enum)
+	// RequirementLevel: Required
+	// Stability: Experimental
+	//
+	// Examples: "ENCODE", "DECODE"
+	CmdStreamCodecOperationKey = attribute.Key("cmd-stream.codec.operation")
+)
+
+const (
+	// CmdStreamCodecElapsedTimeKey is the attribute Key conforming to the
+	// "cmd-stream.codec.elapsed_time" semantic conventions. It represents the
+	// time, in seconds, spent encoding or decoding the Command.
+	//
+	// Type: double
+	// RequirementLevel: Recommended
+	// Stability: Experimental
+	//
+	// Examples: 0.0001, 0.02
+	CmdStreamCodecElapsedTimeKey = attribute.Key("cmd-stream.codec.elapsed_time")
+)
+
+const (
+	// CmdStreamCircuitBreakerStateKey is the attribute Key conforming to the
+	// "cmd-stream.circuit_breaker.state" semantic conventions. It represents
+	// the state of the circuit breaker.
+	//
+	// Type: string (enum)
+	// RequirementLevel: Recommended
+	// Stability: Experimental
+	//
+	// Examples: "CLOSED", "HALF_OPEN", "OPEN"
+	CmdStreamCircuitBreakerStateKey = attribute.Key("cmd-stream.circuit_breaker.state")
+
+	// CmdStreamCircuitBreakerPreviousStateKey is the attribute Key conforming
+	// to the "cmd-stream.circuit_breaker.previous_state" semantic conventions.
+	// It represents the state of the circuit breaker before the transition.
+	//
+	// Type: string (enum)
+	// RequirementLevel: Recommended
+	// Stability: Experimental
+	//
+	// Examples: "CLOSED", "HALF_OPEN", "OPEN"
+	CmdStreamCircuitBreakerPreviousStateKey = attribute.Key("cmd-stream.circuit_breaker.previous_state")
+)
+
+const (
+	// CmdStreamRetryAttemptKey is the attribute Key conforming to the
+	// "cmd-stream.retry.attempt" semantic conventions. It represents the
+	// number of the send attempt within a retried operation, starting from 1.
+	//
+	// Type: int
+	// RequirementLevel: Recommended
+	// Stability: Experimental
+	//
+	// Examples: 1, 2, 3
+	CmdStreamRetryAttemptKey = attribute.Key("cmd-stream.retry.attempt")
+)
+
+const (
+	// CmdStreamResultSendElapsedTimeKey is the attribute Key conforming to the
+	// "cmd-stream.result.send.elapsed_time" semantic conventions. It represents
+	// the time, in seconds, spent sending the Result.
+	//
+	// Type: double
+	// RequirementLevel: Recommended
+	// Stability: Experimental
+	//
+	// Examples: 0.0001, 0.02
+	CmdStreamResultSendElapsedTimeKey = attribute.Key("cmd-stream.result.send.elapsed_time")
+
+	// CmdStreamResultSendDeadlineKey is the attribute Key conforming to the
+	// "cmd-stream.result.send.deadline" semantic conventions. It represents
+	// the write deadline of the Result, in RFC 3339 format.
+	//
+	// Type: string
+	// RequirementLevel: Conditionally Required if the deadline is set.
+	// Stability: Experimental
+	//
+	// Examples: "2025-01-01T00:00:00.5Z"
+	CmdStreamResultSendDeadlineKey = attribute.Key("cmd-stream.result.send.deadline")
+
+	// CmdStreamResultSendDeadlineExceededKey is the attribute Key conforming
+	// to the "cmd-stream.result.send.deadline_exceeded" semantic conventions.
+	// It represents whether the Result send failed because the write deadline
+	// was exceeded.
+	//
+	// Type: boolean
+	// RequirementLevel: Required
+	// Stability: Experimental
+	//
+	// Examples: true, false
+	CmdStreamResultSendDeadlineExceededKey = attribute.Key("cmd-stream.result.send.deadline_exceeded")
+)
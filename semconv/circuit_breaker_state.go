@@ -0,0 +1,30 @@
+package semconv
+
+// CmdStreamCircuitBreakerState represents the circuit breaker state.
+type CmdStreamCircuitBreakerState string
+
+const (
+	// Closed indicates the circuit breaker allows Commands.
+	Closed CmdStreamCircuitBreakerState = "CLOSED"
+
+	// HalfOpen indicates the circuit breaker allows a limited number of
+	// Commands to probe the server.
+	HalfOpen CmdStreamCircuitBreakerState = "HALF_OPEN"
+
+	// Open indicates the circuit breaker rejects Commands.
+	Open CmdStreamCircuitBreakerState = "OPEN"
+)
+
+// Value returns the value of the state reported by the
+// cmd-stream.client.circuit_breaker.state gauge: 0 for Closed, 1 for HalfOpen
+// and 2 for Open.
+func (s CmdStreamCircuitBreakerState) Value() int64 {
+	switch s {
+	case HalfOpen:
+		return 1
+	case Open:
+		return 2
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,22 @@
+package semconv
+
+// CmdStreamConnectionCloseReason represents the reason the connection was
+// closed.
+type CmdStreamConnectionCloseReason string
+
+const (
+	// ClientClosed indicates the client closed the connection.
+	ClientClosed CmdStreamConnectionCloseReason = "CLIENT_CLOSED"
+
+	// ServerClosed indicates the server closed the connection without a prior
+	// read or write error, for example, on shutdown.
+	ServerClosed CmdStreamConnectionCloseReason = "SERVER_CLOSED"
+
+	// TimedOut indicates the connection was closed after a read or write
+	// timeout.
+	TimedOut CmdStreamConnectionCloseReason = "TIMEOUT"
+
+	// ConnError indicates the connection was closed after a read or write
+	// error.
+	ConnError CmdStreamConnectionCloseReason = "ERROR"
+)
@@ -0,0 +1,12 @@
+package semconv
+
+// CmdStreamCodecOperation represents the codec operation.
+type CmdStreamCodecOperation string
+
+const (
+	// Encode indicates a Command or Result was encoded.
+	Encode CmdStreamCodecOperation = "ENCODE"
+
+	// Decode indicates a Command or Result was decoded.
+	Decode CmdStreamCodecOperation = "DECODE"
+)
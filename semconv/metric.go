@@ -120,4 +120,189 @@ const (
 	CmdStreamServerResultCountName        = "cmd-stream.server.result.count"
 	CmdStreamServerResultCountUnit        = "{result}"
 	CmdStreamServerResultCountDescription = "Number of server results."
+
+	// CmdStreamServerCommandExpiredCount is the metric conforming to the
+	// "cmd-stream.server.command.expired" semantic conventions. It represents
+	// the number of commands that arrived at the server after the propagated
+	// client deadline had passed.
+	// Instrument: counter
+	// Unit: {command}
+	// Stability: Experimental
+	CmdStreamServerCommandExpiredCountName        = "cmd-stream.server.command.expired"
+	CmdStreamServerCommandExpiredCountUnit        = "{command}"
+	CmdStreamServerCommandExpiredCountDescription = "Number of server commands that arrived expired."
+
+	// CmdStreamAttributePolicyViolationCount is the metric conforming to the
+	// "cmd-stream.attribute.policy.violations" semantic conventions. It
+	// represents the number of user-supplied attributes that violated the
+	// attribute policy.
+	// Instrument: counter
+	// Unit: {violation}
+	// Stability: Experimental
+	CmdStreamAttributePolicyViolationCountName        = "cmd-stream.attribute.policy.violations"
+	CmdStreamAttributePolicyViolationCountUnit        = "{violation}"
+	CmdStreamAttributePolicyViolationCountDescription = "Number of attribute policy violations."
+
+	// CmdStreamAttributeCardinalityOverflowCount is the metric conforming to
+	// the "cmd-stream.attribute.cardinality.overflow" semantic conventions. It
+	// represents the number of metric attribute values that were replaced with
+	// "_OTHER" because the cardinality limit was reached.
+	// Instrument: counter
+	// Unit: {value}
+	// Stability: Experimental
+	CmdStreamAttributeCardinalityOverflowCountName        = "cmd-stream.attribute.cardinality.overflow"
+	CmdStreamAttributeCardinalityOverflowCountUnit        = "{value}"
+	CmdStreamAttributeCardinalityOverflowCountDescription = "Number of metric attribute values replaced due to the cardinality limit."
+
+	// CmdStreamServerConnectionActive is the metric conforming to the
+	// "cmd-stream.server.connection.active" semantic conventions. It represents
+	// the number of currently open server connections.
+	// Instrument: updowncounter
+	// Unit: {connection}
+	// Stability: Experimental
+	CmdStreamServerConnectionActiveName        = "cmd-stream.server.connection.active"
+	CmdStreamServerConnectionActiveUnit        = "{connection}"
+	CmdStreamServerConnectionActiveDescription = "Number of active server connections."
+
+	// CmdStreamServerConnectionCount is the metric conforming to the
+	// "cmd-stream.server.connection.count" semantic conventions. It represents
+	// the number of closed server connections.
+	// Instrument: counter
+	// Unit: {connection}
+	// Stability: Experimental
+	CmdStreamServerConnectionCountName        = "cmd-stream.server.connection.count"
+	CmdStreamServerConnectionCountUnit        = "{connection}"
+	CmdStreamServerConnectionCountDescription = "Number of closed server connections."
+
+	// CmdStreamServerConnectionDuration is the metric conforming to the
+	// "cmd-stream.server.connection.duration" semantic conventions. It
+	// represents the lifetime of server connections.
+	// Instrument: histogram
+	// Unit: s
+	// Stability: Experimental
+	CmdStreamServerConnectionDurationName        = "cmd-stream.server.connection.duration"
+	CmdStreamServerConnectionDurationUnit        = "s"
+	CmdStreamServerConnectionDurationDescription = "Duration of server connections."
+
+	// CmdStreamClientConnectionAttempts is the metric conforming to the
+	// "cmd-stream.client.connection.attempts" semantic conventions. It
+	// represents the number of connection attempts made by the client.
+	// Instrument: counter
+	// Unit: {attempt}
+	// Stability: Experimental
+	CmdStreamClientConnectionAttemptsName        = "cmd-stream.client.connection.attempts"
+	CmdStreamClientConnectionAttemptsUnit        = "{attempt}"
+	CmdStreamClientConnectionAttemptsDescription = "Number of client connection attempts."
+
+	// CmdStreamClientReconnectAttempts is the metric conforming to the
+	// "cmd-stream.client.reconnect.attempts" semantic conventions. It
+	// represents the number of connection attempts made while reconnecting.
+	// Instrument: counter
+	// Unit: {attempt}
+	// Stability: Experimental
+	CmdStreamClientReconnectAttemptsName        = "cmd-stream.client.reconnect.attempts"
+	CmdStreamClientReconnectAttemptsUnit        = "{attempt}"
+	CmdStreamClientReconnectAttemptsDescription = "Number of client reconnect attempts."
+
+	// CmdStreamClientReconnectDelay is the metric conforming to the
+	// "cmd-stream.client.reconnect.delay" semantic conventions. It represents
+	// the backoff delay before a reconnect attempt.
+	// Instrument: histogram
+	// Unit: s
+	// Stability: Experimental
+	CmdStreamClientReconnectDelayName        = "cmd-stream.client.reconnect.delay"
+	CmdStreamClientReconnectDelayUnit        = "s"
+	CmdStreamClientReconnectDelayDescription = "Backoff delay before a client reconnect attempt."
+
+	// CmdStreamClientKeepaliveRTT is the metric conforming to the
+	// "cmd-stream.client.keepalive.rtt" semantic conventions. It represents the
+	// round-trip time of keepalive Ping-Pong exchanges.
+	// Instrument: histogram
+	// Unit: s
+	// Stability: Experimental
+	CmdStreamClientKeepaliveRTTName        = "cmd-stream.client.keepalive.rtt"
+	CmdStreamClientKeepaliveRTTUnit        = "s"
+	CmdStreamClientKeepaliveRTTDescription = "Round-trip time of keepalive pings."
+
+	// CmdStreamClientDisconnectedDuration is the metric conforming to the
+	// "cmd-stream.client.disconnected.duration" semantic conventions. It
+	// represents the time the client spent disconnected from the server.
+	// Instrument: histogram
+	// Unit: s
+	// Stability: Experimental
+	CmdStreamClientDisconnectedDurationName        = "cmd-stream.client.disconnected.duration"
+	CmdStreamClientDisconnectedDurationUnit        = "s"
+	CmdStreamClientDisconnectedDurationDescription = "Time the client spent disconnected."
+
+	// CmdStreamCodecDuration is the metric conforming to the
+	// "cmd-stream.codec.duration" semantic conventions. It represents the time
+	// spent encoding or decoding a Command or Result.
+	// Instrument: histogram
+	// Unit: s
+	// Stability: Experimental
+	CmdStreamCodecDurationName        = "cmd-stream.codec.duration"
+	CmdStreamCodecDurationUnit        = "s"
+	CmdStreamCodecDurationDescription = "Duration of codec operations."
+
+	// CmdStreamCodecSize is the metric conforming to the
+	// "cmd-stream.codec.size" semantic conventions. It represents the size of
+	// an encoded or decoded Command or Result.
+	// Instrument: histogram
+	// Unit: By
+	// Stability: Experimental
+	CmdStreamCodecSizeName        = "cmd-stream.codec.size"
+	CmdStreamCodecSizeUnit        = "By"
+	CmdStreamCodecSizeDescription = "Size of encoded or decoded data."
+
+	// CmdStreamCodecErrorCount is the metric conforming to the
+	// "cmd-stream.codec.errors" semantic conventions. It represents the number
+	// of failed codec operations.
+	// Instrument: counter
+	// Unit: {error}
+	// Stability: Experimental
+	CmdStreamCodecErrorCountName        = "cmd-stream.codec.errors"
+	CmdStreamCodecErrorCountUnit        = "{error}"
+	CmdStreamCodecErrorCountDescription = "Number of failed codec operations."
+
+	// CmdStreamClientCircuitBreakerState is the metric conforming to the
+	// "cmd-stream.client.circuit_breaker.state" semantic conventions. It
+	// represents the circuit breaker state: 0 - closed, 1 - half-open,
+	// 2 - open.
+	// Instrument: gauge
+	// Unit: {state}
+	// Stability: Experimental
+	CmdStreamClientCircuitBreakerStateName        = "cmd-stream.client.circuit_breaker.state"
+	CmdStreamClientCircuitBreakerStateUnit        = "{state}"
+	CmdStreamClientCircuitBreakerStateDescription = "Circuit breaker state: 0 - closed, 1 - half-open, 2 - open."
+
+	// CmdStreamClientCommandRetryCount is the metric conforming to the
+	// "cmd-stream.client.command.retries" semantic conventions. It represents
+	// the number of retried Command sends.
+	// Instrument: counter
+	// Unit: {retry}
+	// Stability: Experimental
+	CmdStreamClientCommandRetryCountName        = "cmd-stream.client.command.retries"
+	CmdStreamClientCommandRetryCountUnit        = "{retry}"
+	CmdStreamClientCommandRetryCountDescription = "Number of retried Command sends."
+
+	// CmdStreamServerResultSendErrorCount is the metric conforming to the
+	// "cmd-stream.server.result.send_errors" semantic conventions. It
+	// represents the number of Results that failed to be sent.
+	// Instrument: counter
+	// Unit: {error}
+	// Stability: Experimental
+	CmdStreamServerResultSendErrorCountName        = "cmd-stream.server.result.send_errors"
+	CmdStreamServerResultSendErrorCountUnit        = "{error}"
+	CmdStreamServerResultSendErrorCountDescription = "Number of failed Result sends."
+
+	// CmdStreamClientCommandAbandonedCount is the metric conforming to the
+	// "cmd-stream.client.command.abandoned" semantic conventions. It
+	// represents the number of Commands whose spans were ended because the
+	// maximum span lifetime was exceeded.
+	// Instrument: counter
+	// Unit: {command}
+	// Stability: Experimental
+	CmdStreamClientCommandAbandonedCountName        = "cmd-stream.client.command.abandoned"
+	CmdStreamClientCommandAbandonedCountUnit        = "{command}"
+	CmdStreamClientCommandAbandonedCountDescription = "Number of Commands abandoned after the maximum span lifetime."
 )
@@ -0,0 +1,53 @@
+package semconv
+
+import (
+	"os"
+	"strings"
+)
+
+// StabilityOptInEnv is the environment variable used to opt in to the current
+// network semantic conventions.
+const StabilityOptInEnv = "OTEL_SEMCONV_STABILITY_OPT_IN"
+
+// Stability defines which address attributes are emitted.
+type Stability int
+
+const (
+	// StabilityOld emits the original attributes: network.peer.address,
+	// network.peer.port and network.protocol.name equal to the address network.
+	StabilityOld Stability = iota
+
+	// StabilityNew emits the attributes according to the current semantic
+	// conventions: server.address and server.port on the client side,
+	// network.peer.address and network.peer.port on the server side,
+	// network.transport and network.protocol.name equal to "cmd-stream".
+	StabilityNew
+
+	// StabilityMigration emits the new attributes together with the old
+	// network.peer.address and network.peer.port, it can be used to migrate
+	// dashboards gradually. network.protocol.name is present in both sets, but
+	// a span can hold only one value per key, so it is equal to "cmd-stream",
+	// the old value, the address network, is carried by network.transport.
+	StabilityMigration
+)
+
+// StabilityFromEnv returns the Stability specified by the
+// OTEL_SEMCONV_STABILITY_OPT_IN environment variable. It recognizes the
+// "cmd-stream" and "cmd-stream/dup" values, StabilityOld is returned by
+// default.
+func StabilityFromEnv() Stability {
+	return ParseStability(os.Getenv(StabilityOptInEnv))
+}
+
+// ParseStability parses a comma-separated OTEL_SEMCONV_STABILITY_OPT_IN value.
+func ParseStability(value string) (s Stability) {
+	for _, token := range strings.Split(value, ",") {
+		switch strings.TrimSpace(token) {
+		case "cmd-stream/dup":
+			return StabilityMigration
+		case "cmd-stream":
+			s = StabilityNew
+		}
+	}
+	return
+}
@@ -12,4 +12,16 @@ const (
 
 	// Timeout indicates the Command timed out before completion.
 	Timeout CmdStreamCommandStatus = "TIMEOUT"
+
+	// Expired indicates the Command arrived at the server after the client's
+	// deadline had passed, so it was not executed.
+	Expired CmdStreamCommandStatus = "EXPIRED"
+
+	// Rejected indicates the Command was rejected by the circuit breaker, so it
+	// was not sent.
+	Rejected CmdStreamCommandStatus = "REJECTED"
+
+	// Abandoned indicates no Result, error or timeout was received for the
+	// Command within the maximum span lifetime, so its span was ended.
+	Abandoned CmdStreamCommandStatus = "ABANDONED"
 )
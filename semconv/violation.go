@@ -0,0 +1,19 @@
+package semconv
+
+// CmdStreamAttributeViolation represents the kind of the attribute policy
+// violation.
+type CmdStreamAttributeViolation string
+
+const (
+	// Denied indicates the attribute key is in the denied list, the attribute
+	// was dropped.
+	Denied CmdStreamAttributeViolation = "DENIED"
+
+	// NotAllowed indicates the attribute key is not in the allowed list, the
+	// attribute was dropped.
+	NotAllowed CmdStreamAttributeViolation = "NOT_ALLOWED"
+
+	// Truncated indicates the attribute value exceeded the maximum length and
+	// was truncated.
+	Truncated CmdStreamAttributeViolation = "TRUNCATED"
+)
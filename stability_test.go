@@ -0,0 +1,73 @@
+package otelcmd
+
+import (
+	"net"
+	"testing"
+
+	internal_semconv "github.com/cmd-stream/otelcmd-stream-go/internal/semconv"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	asserterror "github.com/ymz-ncnk/assert/error"
+	"go.opentelemetry.io/otel/attribute"
+	otel_semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+)
+
+func TestSemconvStability(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 9000}
+
+	t.Run("ParseStability should recognize opt-in values", func(t *testing.T) {
+		asserterror.Equal(t, semconv.ParseStability(""), semconv.StabilityOld)
+		asserterror.Equal(t, semconv.ParseStability("http, cmd-stream"),
+			semconv.StabilityNew)
+		asserterror.Equal(t, semconv.ParseStability("cmd-stream,cmd-stream/dup"),
+			semconv.StabilityMigration)
+	})
+
+	t.Run("Client attributes should follow the stability", func(t *testing.T) {
+		asserterror.EqualDeep(t,
+			internal_semconv.ClientAddrAttrs(addr, semconv.StabilityOld),
+			[]attribute.KeyValue{
+				otel_semconv.NetworkPeerAddress("127.0.0.1"),
+				otel_semconv.NetworkPeerPort(9000),
+				otel_semconv.NetworkProtocolName("tcp"),
+			})
+		asserterror.EqualDeep(t,
+			internal_semconv.ClientAddrAttrs(addr, semconv.StabilityNew),
+			[]attribute.KeyValue{
+				otel_semconv.ServerAddress("127.0.0.1"),
+				otel_semconv.ServerPort(9000),
+				otel_semconv.NetworkTransportTCP,
+				otel_semconv.NetworkProtocolName("cmd-stream"),
+			})
+		asserterror.EqualDeep(t,
+			internal_semconv.ClientAddrAttrs(addr, semconv.StabilityMigration),
+			[]attribute.KeyValue{
+				otel_semconv.NetworkPeerAddress("127.0.0.1"),
+				otel_semconv.NetworkPeerPort(9000),
+				otel_semconv.ServerAddress("127.0.0.1"),
+				otel_semconv.ServerPort(9000),
+				otel_semconv.NetworkTransportTCP,
+				otel_semconv.NetworkProtocolName("cmd-stream"),
+			})
+	})
+
+	t.Run("Unix address should not have a port", func(t *testing.T) {
+		asserterror.EqualDeep(t, internal_semconv.ClientAddrAttrs(
+			&net.UnixAddr{Name: "/tmp/cmd.sock", Net: "unix"}, semconv.StabilityNew),
+			[]attribute.KeyValue{
+				otel_semconv.ServerAddress("/tmp/cmd.sock"),
+				otel_semconv.NetworkTransportUnix,
+				otel_semconv.NetworkProtocolName("cmd-stream"),
+			})
+	})
+
+	t.Run("Server attributes should follow the stability", func(t *testing.T) {
+		asserterror.EqualDeep(t,
+			internal_semconv.ServerAddrAttrs(addr, semconv.StabilityNew),
+			[]attribute.KeyValue{
+				otel_semconv.NetworkPeerAddress("127.0.0.1"),
+				otel_semconv.NetworkPeerPort(9000),
+				otel_semconv.NetworkTransportTCP,
+				otel_semconv.NetworkProtocolName("cmd-stream"),
+			})
+	})
+}
@@ -0,0 +1,78 @@
+package otelcmd
+
+import (
+	"github.com/cmd-stream/cmd-stream-go/core"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanAttributer can be implemented by a Command to add its own span
+// attributes.
+type SpanAttributer interface {
+	SpanAttributes() []attribute.KeyValue
+}
+
+// MetricAttributer can be implemented by a Command to add its own metric
+// attributes. Beware of the metric cardinality.
+type MetricAttributer interface {
+	MetricAttributes() []attribute.KeyValue
+}
+
+// SpanNamer can be implemented by a Command to define its own client span
+// name, it takes precedence over SpanNameFormatter. Server spans are named by
+// the Invoker's SpanNameFormatter only.
+type SpanNamer interface {
+	SpanName() string
+}
+
+// SpanKinder can be implemented by a Command to define its own client span
+// kind. Server spans always have the kind set by the Invoker options.
+type SpanKinder interface {
+	SpanKind() trace.SpanKind
+}
+
+// ResultEventAttributer can be implemented by a Result to add its own result
+// event attributes.
+type ResultEventAttributer interface {
+	ResultEventAttributes() []attribute.KeyValue
+}
+
+// unwrapCmd returns the Command wrapped by TraceCmd, or the Command itself.
+func unwrapCmd[T any](cmd core.Cmd[T]) core.Cmd[T] {
+	if tcmd, ok := cmd.(traceCmd[T]); ok {
+		return tcmd.InnerCmd()
+	}
+	return cmd
+}
+
+func (o Options[T]) spanName(cmd core.Cmd[T]) string {
+	if namer, ok := unwrapCmd(cmd).(SpanNamer); ok {
+		return namer.SpanName()
+	}
+	return o.SpanNameFormatter(cmd)
+}
+
+func (o Options[T]) spanStartOptions(cmd core.Cmd[T]) []trace.SpanStartOption {
+	kinder, ok := unwrapCmd(cmd).(SpanKinder)
+	if !ok {
+		return o.SpanStartOptions
+	}
+	l := len(o.SpanStartOptions)
+	opts := make([]trace.SpanStartOption, l, l+1)
+	copy(opts, o.SpanStartOptions)
+	return append(opts, trace.WithSpanKind(kinder.SpanKind()))
+}
+
+func cmdSpanAttributes[T any](cmd core.Cmd[T]) []attribute.KeyValue {
+	if attributer, ok := unwrapCmd(cmd).(SpanAttributer); ok {
+		return attributer.SpanAttributes()
+	}
+	return nil
+}
+
+func cmdMetricAttributes[T any](cmd core.Cmd[T]) []attribute.KeyValue {
+	if attributer, ok := unwrapCmd(cmd).(MetricAttributer); ok {
+		return attributer.MetricAttributes()
+	}
+	return nil
+}
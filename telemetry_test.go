@@ -0,0 +1,112 @@
+package otelcmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cmd-stream/cmd-stream-go/core"
+	"github.com/cmd-stream/cmd-stream-go/sender/hooks"
+	cmock "github.com/cmd-stream/cmd-stream-go/test/mock"
+	"github.com/cmd-stream/otelcmd-stream-go/semconv"
+	asserterror "github.com/ymz-ncnk/assert/error"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTelemetryInterfaces(t *testing.T) {
+	var (
+		cmd     = NewTraceCmd[any](telemetryCmd{Cmd: cmock.NewCmd[any]()})
+		sentCmd = hooks.SentCmd[any]{Cmd: cmd}
+		o       = NewHooksFactory[any](
+			WithMeterProvider[any](noop.NewMeterProvider()),
+			WithCmdMetricAttributesFn(StaticMetricAttributes[any](
+				attribute.String("app", "a1"),
+				attribute.String("op", "default"),
+			)),
+		).options
+	)
+
+	t.Run("SpanNamer should take precedence over SpanNameFormatter",
+		func(t *testing.T) {
+			asserterror.Equal(t, o.spanName(cmd), "Send Telemetry")
+		})
+
+	t.Run("SpanKinder should override the span kind", func(t *testing.T) {
+		config := trace.NewSpanStartConfig(o.spanStartOptions(cmd)...)
+		asserterror.Equal(t, config.SpanKind(), trace.SpanKindProducer)
+		config = trace.NewSpanStartConfig(o.SpanStartOptions...)
+		asserterror.Equal(t, config.SpanKind(), trace.SpanKindClient)
+	})
+
+	t.Run("SpanAttributer attributes should be added", func(t *testing.T) {
+		asserterror.EqualDeep(t, o.userSpanAttributes(context.Background(), nil,
+			sentCmd), []attribute.KeyValue{attribute.String("op", "telemetry")})
+	})
+
+	t.Run("MetricAttributer attributes should be merged", func(t *testing.T) {
+		asserterror.EqualDeep(t, o.userCmdMetricAttributes(context.Background(),
+			sentCmd, semconv.Ok, 0), []attribute.KeyValue{
+			attribute.String("app", "a1"),
+			attribute.String("op", "telemetry"),
+		})
+	})
+
+	t.Run("ResultEventAttributer attributes should be added", func(t *testing.T) {
+		attrs, ok := o.userResultEventAttributes(context.Background(), sentCmd,
+			hooks.ReceivedResult{Result: telemetryResult{}})
+		asserterror.Equal(t, ok, true)
+		asserterror.EqualDeep(t, attrs,
+			[]attribute.KeyValue{attribute.Bool("cached", true)})
+
+		_, ok = o.userResultEventAttributes(context.Background(), sentCmd,
+			hooks.ReceivedResult{Result: cmock.NewResult()})
+		asserterror.Equal(t, ok, false)
+	})
+}
+
+func TestCustomTraceCmd(t *testing.T) {
+	var (
+		inner = cmock.NewCmd[any]()
+		cmd   = customTraceCmd{Cmd: inner}
+	)
+	_, ok := core.Cmd[any](cmd).(traceCmd[any])
+	asserterror.Equal(t, ok, true)
+	asserterror.Equal(t, unwrapCmd[any](cmd), core.Cmd[any](inner))
+	asserterror.Equal(t, cmdState[any](cmd) == nil, true)
+}
+
+// customTraceCmd implements traceCmd without the TraceCmd state.
+type customTraceCmd struct {
+	cmock.Cmd[any]
+}
+
+func (c customTraceCmd) SetCarrier(carrier map[string]string) {}
+
+func (c customTraceCmd) Carrier() map[string]string { return nil }
+
+func (c customTraceCmd) InnerCmd() core.Cmd[any] { return c.Cmd }
+
+type telemetryCmd struct {
+	cmock.Cmd[any]
+}
+
+func (c telemetryCmd) SpanName() string { return "Send Telemetry" }
+
+func (c telemetryCmd) SpanKind() trace.SpanKind { return trace.SpanKindProducer }
+
+func (c telemetryCmd) SpanAttributes() []attribute.KeyValue {
+	return []attribute.KeyValue{attribute.String("op", "telemetry")}
+}
+
+func (c telemetryCmd) MetricAttributes() []attribute.KeyValue {
+	return []attribute.KeyValue{attribute.String("op", "telemetry")}
+}
+
+type telemetryResult struct{}
+
+func (r telemetryResult) LastOne() bool { return true }
+
+func (r telemetryResult) ResultEventAttributes() []attribute.KeyValue {
+	return []attribute.KeyValue{attribute.Bool("cached", true)}
+}
@@ -0,0 +1,30 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/ymz-ncnk/mok"
+	"go.opentelemetry.io/otel/metric"
+)
+
+func NewInt64UpDownCounter() Int64UpDownCounter {
+	return Int64UpDownCounter{Mock: mok.New("Int64UpDownCounter")}
+}
+
+type Int64UpDownCounter struct {
+	*mok.Mock
+	metric.Int64UpDownCounter
+}
+
+func (c Int64UpDownCounter) RegisterAdd(fn AddFn) Int64UpDownCounter {
+	c.Register("Add", fn)
+	return c
+}
+
+func (c Int64UpDownCounter) Add(ctx context.Context, incr int64,
+	options ...metric.AddOption) {
+	_, err := c.Call("Add", ctx, incr, options)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -8,6 +8,8 @@ import (
 type Int64CounterFn func(name string, options ...metric.Int64CounterOption) (metric.Int64Counter, error)
 type Int64HistogramFn func(name string, options ...metric.Int64HistogramOption) (metric.Int64Histogram, error)
 type Float64HistogramFn func(name string, options ...metric.Float64HistogramOption) (metric.Float64Histogram, error)
+type Int64UpDownCounterFn func(name string, options ...metric.Int64UpDownCounterOption) (metric.Int64UpDownCounter, error)
+type Int64ObservableGaugeFn func(name string, options ...metric.Int64ObservableGaugeOption) (metric.Int64ObservableGauge, error)
 
 func NewMeter() Meter {
 	return Meter{Mock: mok.New("Meter")}
@@ -33,6 +35,16 @@ func (m Meter) RegisterFloat64Histogram(fn Float64HistogramFn) Meter {
 	return m
 }
 
+func (m Meter) RegisterInt64UpDownCounter(fn Int64UpDownCounterFn) Meter {
+	m.Register("Int64UpDownCounter", fn)
+	return m
+}
+
+func (m Meter) RegisterInt64ObservableGauge(fn Int64ObservableGaugeFn) Meter {
+	m.Register("Int64ObservableGauge", fn)
+	return m
+}
+
 func (m Meter) Int64Counter(name string, options ...metric.Int64CounterOption) (c metric.Int64Counter, err error) {
 	results, err := m.Call("Int64Counter", name, options)
 	if err != nil {
@@ -63,8 +75,14 @@ func (m Meter) Float64Histogram(name string, options ...metric.Float64HistogramO
 	return
 }
 
-func (m Meter) Int64UpDownCounter(name string, options ...metric.Int64UpDownCounterOption) (metric.Int64UpDownCounter, error) {
-	panic("not implemented")
+func (m Meter) Int64UpDownCounter(name string, options ...metric.Int64UpDownCounterOption) (c metric.Int64UpDownCounter, err error) {
+	results, err := m.Call("Int64UpDownCounter", name, options)
+	if err != nil {
+		panic(err)
+	}
+	c, _ = results[0].(metric.Int64UpDownCounter)
+	err, _ = results[1].(error)
+	return
 }
 
 func (m Meter) Int64Gauge(name string, options ...metric.Int64GaugeOption) (metric.Int64Gauge, error) {
@@ -79,8 +97,14 @@ func (m Meter) Int64ObservableUpDownCounter(name string, options ...metric.Int64
 	panic("not implemented")
 }
 
-func (m Meter) Int64ObservableGauge(name string, options ...metric.Int64ObservableGaugeOption) (metric.Int64ObservableGauge, error) {
-	panic("not implemented")
+func (m Meter) Int64ObservableGauge(name string, options ...metric.Int64ObservableGaugeOption) (g metric.Int64ObservableGauge, err error) {
+	results, err := m.Call("Int64ObservableGauge", name, options)
+	if err != nil {
+		panic(err)
+	}
+	g, _ = results[0].(metric.Int64ObservableGauge)
+	err, _ = results[1].(error)
+	return
 }
 
 func (m Meter) Float64Counter(name string, options ...metric.Float64CounterOption) (metric.Float64Counter, error) {
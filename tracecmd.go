@@ -2,6 +2,7 @@ package otelcmd
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	"github.com/cmd-stream/cmd-stream-go/core"
@@ -14,11 +15,39 @@ type traceCmd[T any] interface {
 	InnerCmd() core.Cmd[T]
 }
 
+// stateCmd is implemented by TraceCmd in addition to traceCmd. Other traceCmd
+// implementations lack the per-Command state, so they get no codec events
+// and client notifications.
+type stateCmd[T any] interface {
+	state() *traceCmdState[T]
+	withDecodeEvent(event decodeEvent) core.Cmd[T]
+}
+
+// cmdState returns the state of the Command, or nil if it has none.
+func cmdState[T any](cmd core.Cmd[T]) *traceCmdState[T] {
+	if scmd, ok := cmd.(stateCmd[T]); ok {
+		return scmd.state()
+	}
+	return nil
+}
+
+// traceCmdState is shared by the copies of a TraceCmd, it lets the client
+// and the codecs, which don't receive a context, reach the telemetry of the
+// Command. It is collected together with the Command, so nothing has to be
+// cleaned up.
+type traceCmdState[T any] struct {
+	// hooks are the client Hooks of the Command.
+	hooks atomic.Pointer[Hooks[T]]
+	// decoded is the decoded event recorded by the ServerCodec.
+	decoded *decodeEvent
+}
+
 // NewTraceCmd creates a new TraceCmd.
 func NewTraceCmd[T any, V core.Cmd[T]](cmd V) TraceCmd[T, V] {
 	return TraceCmd[T, V]{
 		MapCarrier: new(map[string]string),
 		Cmd:        cmd,
+		cmdState:   &traceCmdState[T]{},
 	}
 }
 
@@ -26,6 +55,7 @@ func NewTraceCmd[T any, V core.Cmd[T]](cmd V) TraceCmd[T, V] {
 type TraceCmd[T any, V core.Cmd[T]] struct {
 	MapCarrier *map[string]string
 	Cmd        V
+	cmdState   *traceCmdState[T]
 }
 
 func (c TraceCmd[T, V]) Exec(ctx context.Context, seq core.Seq, at time.Time,
@@ -54,3 +84,13 @@ func (c TraceCmd[T, V]) Carrier() (carrier map[string]string) {
 func (c TraceCmd[T, V]) InnerCmd() core.Cmd[T] {
 	return c.Cmd
 }
+
+func (c TraceCmd[T, V]) state() *traceCmdState[T] {
+	return c.cmdState
+}
+
+// withDecodeEvent returns a copy of the TraceCmd with the decoded event.
+func (c TraceCmd[T, V]) withDecodeEvent(event decodeEvent) core.Cmd[T] {
+	c.cmdState = &traceCmdState[T]{decoded: &event}
+	return c
+}